@@ -10,7 +10,7 @@ import (
 
 func main() {
 	// Instantiate a new client to retrieve data from the Hacker News API
-	hn, err := gohn.NewClient(nil)
+	hn, err := gohn.NewClient()
 	if err != nil {
 		panic(err)
 	}
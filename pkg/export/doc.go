@@ -0,0 +1,16 @@
+/*
+Package export renders a story and its comments as Markdown, HTML, JSON or plain text.
+
+The comments are rendered nested under their parent, as returned by gohn.NewCommentTree.
+Comments that cannot be reached from the story (see gohn.CommentTree.Orphans) are not rendered.
+
+Example:
+
+	story := &gohn.Story{Parent: item, CommentsByIdMap: comments}
+	opts := export.DefaultOptions()
+	opts.MaxDepth = 3
+	if err := export.Markdown(os.Stdout, story, opts); err != nil {
+		panic(err)
+	}
+*/
+package export
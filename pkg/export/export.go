@@ -0,0 +1,119 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// TimeFormat is the layout used to render the time of the items, always in UTC.
+const TimeFormat = "2006-01-02 15:04 UTC"
+
+// Options configures the rendering of a story.
+type Options struct {
+	// MaxDepth limits how deep in the thread comments are rendered:
+	// 1 renders only the top-level comments, 2 their replies too, and so on.
+	// If zero, there is no limit.
+	MaxDepth int
+	// IncludeDeleted renders deleted and dead comments as a placeholder.
+	// Otherwise they are left out together with their replies.
+	IncludeDeleted bool
+	// Timestamps renders the time of the story and of each comment.
+	Timestamps bool
+	// Authors renders the author of the story and of each comment.
+	Authors bool
+	// Sort, if not nil, orders the comments (see gohn.CommentTree.Sort).
+	// Otherwise they are in the same order as on the website.
+	Sort gohn.SortStrategy
+}
+
+// DefaultOptions returns the Options used when nil is passed to the renderers:
+// all the comments, without the deleted and dead ones, with timestamps and authors.
+func DefaultOptions() *Options {
+	return &Options{Timestamps: true, Authors: true}
+}
+
+// render builds the comment tree of story and calls fn to render it into
+// a buffer, which is written to w only if fn succeeds.
+func render(w io.Writer, story *gohn.Story, opts *Options, fn func(*bytes.Buffer, *gohn.CommentTree, *Options) error) error {
+	if opts == nil {
+		opts = DefaultOptions()
+	}
+	tree, err := gohn.NewCommentTree(story)
+	if err != nil {
+		return err
+	}
+	if opts.Sort != nil {
+		tree.Sort(opts.Sort)
+	}
+	var buf bytes.Buffer
+	if err := fn(&buf, tree, opts); err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// visible returns the nodes that are rendered.
+func (o *Options) visible(nodes []*gohn.CommentNode) []*gohn.CommentNode {
+	var visible []*gohn.CommentNode
+	for _, node := range nodes {
+		if o.MaxDepth > 0 && node.Depth > o.MaxDepth {
+			continue
+		}
+		if !o.IncludeDeleted && (node.Item.IsDeleted() || node.Item.IsDead()) {
+			continue
+		}
+		visible = append(visible, node)
+	}
+	return visible
+}
+
+// meta returns the author and time of item, according to opts.
+func (o *Options) meta(item *gohn.Item) []string {
+	var meta []string
+	if o.Authors && item.GetBy() != "" {
+		meta = append(meta, item.GetBy())
+	}
+	if o.Timestamps && item.Time != nil {
+		meta = append(meta, formatTime(item.GetTime()))
+	}
+	return meta
+}
+
+// storyMeta returns the score, author, time and number of comments of story, according to opts.
+func (o *Options) storyMeta(story *gohn.Item) string {
+	var meta []string
+	if story.Score != nil {
+		meta = append(meta, fmt.Sprintf("%d points", story.GetScore()))
+	}
+	if o.Authors && story.GetBy() != "" {
+		meta = append(meta, "by "+story.GetBy())
+	}
+	if o.Timestamps && story.Time != nil {
+		meta = append(meta, formatTime(story.GetTime()))
+	}
+	if story.Descendants != nil {
+		meta = append(meta, fmt.Sprintf("%d comments", story.GetDescendants()))
+	}
+	return strings.Join(meta, " | ")
+}
+
+// placeholder returns the text rendered instead of a deleted or dead item, or "".
+func placeholder(item *gohn.Item) string {
+	switch {
+	case item.IsDeleted():
+		return "[deleted]"
+	case item.IsDead():
+		return "[dead]"
+	}
+	return ""
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(TimeFormat)
+}
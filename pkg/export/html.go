@@ -0,0 +1,66 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+)
+
+// HTML writes story to w as a standalone HTML document.
+// The comments are nested lists. The text of the story and of the comments
+// is sanitized with processors.Sanitize and the title links to the URL
+// of the story only if it is an http or https URL.
+// A nil opts is equivalent to DefaultOptions().
+func HTML(w io.Writer, story *gohn.Story, opts *Options) error {
+	return render(w, story, opts, func(buf *bytes.Buffer, tree *gohn.CommentTree, opts *Options) error {
+		s := tree.Story
+		title := html.EscapeString(s.GetTitle())
+		buf.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+		fmt.Fprintf(buf, "<title>%s</title>\n</head>\n<body>\n<article>\n", title)
+		if isWebURL(s.GetURL()) {
+			fmt.Fprintf(buf, "<h1><a href=\"%s\">%s</a></h1>\n", html.EscapeString(s.GetURL()), title)
+		} else {
+			fmt.Fprintf(buf, "<h1>%s</h1>\n", title)
+		}
+		if meta := opts.storyMeta(s); meta != "" {
+			fmt.Fprintf(buf, "<p class=\"meta\">%s</p>\n", html.EscapeString(meta))
+		}
+		if s.Text != nil {
+			fmt.Fprintf(buf, "<div class=\"text\">%s</div>\n", processors.Sanitize(s.GetText()))
+		}
+		htmlComments(buf, opts.visible(tree.Roots), opts)
+		buf.WriteString("</article>\n</body>\n</html>\n")
+		return nil
+	})
+}
+
+func htmlComments(buf *bytes.Buffer, nodes []*gohn.CommentNode, opts *Options) {
+	if len(nodes) == 0 {
+		return
+	}
+	buf.WriteString("<ul class=\"comments\">\n")
+	for _, node := range nodes {
+		fmt.Fprintf(buf, "<li id=\"item-%d\">\n", node.Item.GetID())
+		if meta := opts.meta(node.Item); len(meta) > 0 {
+			fmt.Fprintf(buf, "<p class=\"meta\">%s</p>\n", html.EscapeString(strings.Join(meta, " | ")))
+		}
+		if text := placeholder(node.Item); text != "" {
+			fmt.Fprintf(buf, "<p class=\"placeholder\">%s</p>\n", text)
+		} else {
+			fmt.Fprintf(buf, "<div class=\"text\">%s</div>\n", processors.Sanitize(node.Item.GetText()))
+		}
+		htmlComments(buf, opts.visible(node.Children), opts)
+		buf.WriteString("</li>\n")
+	}
+	buf.WriteString("</ul>\n")
+}
+
+func isWebURL(s string) bool {
+	lower := strings.ToLower(strings.TrimSpace(s))
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
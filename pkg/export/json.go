@@ -0,0 +1,76 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// jsonItem is the JSON representation of the story and of each comment.
+type jsonItem struct {
+	ID          int         `json:"id"`
+	Type        string      `json:"type,omitempty"`
+	By          string      `json:"by,omitempty"`
+	Time        string      `json:"time,omitempty"`
+	Title       string      `json:"title,omitempty"`
+	URL         string      `json:"url,omitempty"`
+	Text        string      `json:"text,omitempty"`
+	Score       int         `json:"score,omitempty"`
+	Descendants int         `json:"descendants,omitempty"`
+	Deleted     bool        `json:"deleted,omitempty"`
+	Dead        bool        `json:"dead,omitempty"`
+	Comments    []*jsonItem `json:"comments,omitempty"`
+}
+
+// JSON writes story to w as indented JSON. The story is an object
+// with the fields of the API (time in RFC 3339 format) and its comments
+// are nested in the "comments" field of their parent.
+// Deleted and dead comments, if included, only have their ID, type and flag.
+// A nil opts is equivalent to DefaultOptions().
+func JSON(w io.Writer, story *gohn.Story, opts *Options) error {
+	return render(w, story, opts, func(buf *bytes.Buffer, tree *gohn.CommentTree, opts *Options) error {
+		root := newJSONItem(tree.Story, opts)
+		root.Title = tree.Story.GetTitle()
+		root.URL = tree.Story.GetURL()
+		root.Score = tree.Story.GetScore()
+		root.Descendants = tree.Story.GetDescendants()
+		root.Comments = jsonComments(opts.visible(tree.Roots), opts)
+
+		enc := json.NewEncoder(buf)
+		enc.SetIndent("", "  ")
+		return enc.Encode(root)
+	})
+}
+
+func newJSONItem(item *gohn.Item, opts *Options) *jsonItem {
+	j := &jsonItem{
+		ID:      item.GetID(),
+		Type:    string(item.Kind()),
+		Deleted: item.IsDeleted(),
+		Dead:    item.IsDead(),
+	}
+	if placeholder(item) != "" {
+		return j
+	}
+	j.Text = item.GetText()
+	if opts.Authors {
+		j.By = item.GetBy()
+	}
+	if opts.Timestamps && item.Time != nil {
+		j.Time = item.GetTime().UTC().Format(time.RFC3339)
+	}
+	return j
+}
+
+func jsonComments(nodes []*gohn.CommentNode, opts *Options) []*jsonItem {
+	var comments []*jsonItem
+	for _, node := range nodes {
+		j := newJSONItem(node.Item, opts)
+		j.Comments = jsonComments(opts.visible(node.Children), opts)
+		comments = append(comments, j)
+	}
+	return comments
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+)
+
+// Markdown writes story to w as Markdown. The comments are nested blockquotes.
+// The text of the story and of the comments is converted with processors.ToMarkdown,
+// the other fields are escaped and the title links to the URL of the story
+// only if it is an http or https URL.
+// A nil opts is equivalent to DefaultOptions().
+func Markdown(w io.Writer, story *gohn.Story, opts *Options) error {
+	return render(w, story, opts, func(buf *bytes.Buffer, tree *gohn.CommentTree, opts *Options) error {
+		s := tree.Story
+		title := processors.EscapeMarkdown(s.GetTitle())
+		if isWebURL(s.GetURL()) {
+			fmt.Fprintf(buf, "# [%s](%s)\n\n", title, markdownURLEscaper.Replace(strings.TrimSpace(s.GetURL())))
+		} else {
+			fmt.Fprintf(buf, "# %s\n\n", title)
+		}
+		if meta := opts.storyMeta(s); meta != "" {
+			fmt.Fprintf(buf, "*%s*\n\n", processors.EscapeMarkdown(meta))
+		}
+		if text := processors.ToMarkdown(s.GetText()); text != "" {
+			fmt.Fprintf(buf, "%s\n\n", text)
+		}
+		buf.WriteString("---\n\n")
+		markdownComments(buf, opts.visible(tree.Roots), opts)
+		return nil
+	})
+}
+
+func markdownComments(buf *bytes.Buffer, nodes []*gohn.CommentNode, opts *Options) {
+	for _, node := range nodes {
+		prefix := strings.Repeat("> ", node.Depth)
+		writeLines := func(text string) {
+			for _, line := range strings.Split(text, "\n") {
+				buf.WriteString(strings.TrimRight(prefix+line, " ") + "\n")
+			}
+		}
+		if meta := opts.meta(node.Item); len(meta) > 0 {
+			for i := range meta {
+				meta[i] = processors.EscapeMarkdown(meta[i])
+			}
+			meta[0] = "**" + meta[0] + "**"
+			writeLines(strings.Join(meta, " | ") + "\n")
+		}
+		if text := placeholder(node.Item); text != "" {
+			writeLines(text)
+		} else {
+			writeLines(processors.ToMarkdown(node.Item.GetText()))
+		}
+		if children := opts.visible(node.Children); len(children) > 0 {
+			// the replies are quoted inside the quote of their parent
+			buf.WriteString(strings.TrimSpace(prefix) + "\n")
+			markdownComments(buf, children, opts)
+		}
+		// an empty line at the depth of the parent closes the quote
+		buf.WriteString(strings.TrimSpace(strings.Repeat("> ", node.Depth-1)) + "\n")
+	}
+}
+
+// markdownURLEscaper escapes the characters that would end a link in Markdown.
+var markdownURLEscaper = strings.NewReplacer("(", "%28", ")", "%29", "<", "%3C", ">", "%3E", " ", "%20")
@@ -0,0 +1,53 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+)
+
+// Text writes story to w as plain text. The replies are indented
+// by two spaces under their parent.
+// A nil opts is equivalent to DefaultOptions().
+func Text(w io.Writer, story *gohn.Story, opts *Options) error {
+	return render(w, story, opts, func(buf *bytes.Buffer, tree *gohn.CommentTree, opts *Options) error {
+		s := tree.Story
+		fmt.Fprintln(buf, s.GetTitle())
+		if s.URL != nil {
+			fmt.Fprintln(buf, s.GetURL())
+		}
+		if meta := opts.storyMeta(s); meta != "" {
+			fmt.Fprintln(buf, meta)
+		}
+		if text := processors.ToText(s.GetText()); text != "" {
+			fmt.Fprintf(buf, "\n%s\n", text)
+		}
+		buf.WriteString("\n----\n\n")
+		textComments(buf, opts.visible(tree.Roots), opts)
+		return nil
+	})
+}
+
+func textComments(buf *bytes.Buffer, nodes []*gohn.CommentNode, opts *Options) {
+	for _, node := range nodes {
+		indent := strings.Repeat("  ", node.Depth-1)
+		var lines []string
+		if meta := opts.meta(node.Item); len(meta) > 0 {
+			lines = append(lines, strings.Join(meta, " | "))
+		}
+		text := placeholder(node.Item)
+		if text == "" {
+			text = processors.ToText(node.Item.GetText())
+		}
+		lines = append(lines, strings.Split(text, "\n")...)
+		for _, line := range lines {
+			buf.WriteString(strings.TrimRight(indent+line, " ") + "\n")
+		}
+		buf.WriteString("\n")
+		textComments(buf, opts.visible(node.Children), opts)
+	}
+}
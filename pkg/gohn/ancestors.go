@@ -0,0 +1,83 @@
+package gohn
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrCycle is reported when following the parents of an item leads back to an item already seen.
+var ErrCycle = errors.New("cycle in the parents of the item")
+
+// Ancestor is an item returned by ItemsService.GetAncestors.
+type Ancestor struct {
+	Item *Item
+	// Depth is the distance from the item passed to GetAncestors:
+	// 1 for its parent, 2 for the parent of the parent, and so on.
+	Depth int
+}
+
+// GetAncestors returns the ancestors of item, starting from its parent
+// up to the root of the thread (usually a story, but it can also be a poll or a job).
+// Pollopts have their poll as parent. The ancestors are retrieved one at a time,
+// so a Client with a Cache (see WithCache) avoids retrieving them again
+// when the ancestors of several comments of the same thread are needed.
+// If a parent cannot be retrieved, or is null, an *ItemError with its ID is returned
+// together with the ancestors found so far. The same happens, with ErrCycle,
+// if the chain of parents loops. The Parent of the ItemError is not set,
+// since the parent of the failed item is unknown.
+func (s *ItemsService) GetAncestors(ctx context.Context, item *Item) ([]Ancestor, error) {
+	if item == nil {
+		return nil, InvalidItemError{Message: "item is nil"}
+	}
+	seen := map[int]bool{}
+	if item.ID != nil {
+		seen[*item.ID] = true
+	}
+	var ancestors []Ancestor
+	for depth := 1; ; depth++ {
+		parentID, ok := parentOf(item)
+		if !ok {
+			return ancestors, nil
+		}
+		if seen[parentID] {
+			return ancestors, &ItemError{ID: parentID, Err: ErrCycle}
+		}
+		seen[parentID] = true
+
+		parent, err := s.Get(ctx, parentID)
+		if err == nil && parent == nil {
+			err = ErrItemNotFound
+		}
+		if err != nil {
+			return ancestors, &ItemError{ID: parentID, Err: err}
+		}
+		ancestors = append(ancestors, Ancestor{Item: parent, Depth: depth})
+		item = parent
+	}
+}
+
+// GetRoot returns the root of the thread item belongs to,
+// i.e. the last of its ancestors, or item itself if it has no parent.
+// See GetAncestors.
+func (s *ItemsService) GetRoot(ctx context.Context, item *Item) (*Item, error) {
+	ancestors, err := s.GetAncestors(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if len(ancestors) == 0 {
+		return item, nil
+	}
+	return ancestors[len(ancestors)-1].Item, nil
+}
+
+// parentOf returns the ID of the parent of item:
+// its Parent or, for pollopts, its Poll.
+func parentOf(item *Item) (int, bool) {
+	if item.Parent != nil {
+		return *item.Parent, true
+	}
+	if item.Is(ItemTypePollOpt) && item.Poll != nil {
+		return *item.Poll, true
+	}
+	return 0, false
+}
@@ -0,0 +1,285 @@
+package gohn
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NeverExpire can be returned by a CachePolicy to keep a response
+// in the cache until it is evicted or deleted.
+const NeverExpire time.Duration = -1
+
+// Cache stores raw API response bodies keyed by request URL.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the body stored for key and true,
+	// or nil and false if the key is missing or expired.
+	Get(key string) ([]byte, bool)
+	// Set stores body for key for the given ttl.
+	// A negative ttl (see NeverExpire) means the entry never expires.
+	Set(key string, body []byte, ttl time.Duration)
+	// Delete removes key from the cache.
+	Delete(key string)
+}
+
+// CachePolicy returns how long the response body of the given endpoint
+// should be cached. path is relative to the BaseURL of the Client (e.g. "item/1.json").
+// A zero duration means that the response is not cached.
+type CachePolicy func(path string, body []byte) time.Duration
+
+// CacheStats reports how many requests were served from the cache
+// and how many had to go to the network.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// CacheTTLs holds the time-to-live used by the default CachePolicy
+// for each family of endpoints.
+type CacheTTLs struct {
+	// Live is used for endpoints that change every few seconds:
+	// the story lists, maxitem.json and updates.json.
+	Live time.Duration
+	// Users is used for user profiles.
+	Users time.Duration
+	// Items is used for items younger than OldItemAge.
+	Items time.Duration
+	// OldItems is used for items older than OldItemAge,
+	// which are not expected to change anymore.
+	OldItems time.Duration
+	// OldItemAge is the age after which an item is considered old.
+	OldItemAge time.Duration
+}
+
+// DefaultCacheTTLs returns the TTLs used when WithCache is given a nil CachePolicy.
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Live:       10 * time.Second,
+		Users:      5 * time.Minute,
+		Items:      time.Minute,
+		OldItems:   NeverExpire,
+		OldItemAge: 14 * 24 * time.Hour,
+	}
+}
+
+// Policy returns a CachePolicy that applies t to the Hacker News API endpoints.
+// Responses of unknown endpoints are not cached.
+func (t CacheTTLs) Policy() CachePolicy {
+	return func(path string, body []byte) time.Duration {
+		switch {
+		case path == MAX_ITEM_ID_URL, path == UPDATES_URL, isStoriesListPath(path):
+			return t.Live
+		case strings.HasPrefix(path, "user/"):
+			return t.Users
+		case strings.HasPrefix(path, "item/"):
+			var item struct {
+				Time *int64 `json:"time"`
+			}
+			if err := json.Unmarshal(body, &item); err == nil && item.Time != nil {
+				if time.Since(time.Unix(*item.Time, 0)) > t.OldItemAge {
+					return t.OldItems
+				}
+			}
+			return t.Items
+		}
+		return 0
+	}
+}
+
+func isStoriesListPath(path string) bool {
+	switch path {
+	case TOP_STORIES_URL, BEST_STORIES_URL, NEW_STORIES_URL,
+		ASK_STORIES_URL, SHOW_STORIES_URL, JOB_STORIES_URL:
+		return true
+	}
+	return false
+}
+
+// skipCacheKey is the context key set by skipCache.
+type skipCacheKey struct{}
+
+// skipCache returns a copy of ctx for which Client.Do does not serve
+// the responses from the cache, for the data known to have changed.
+// The fresh responses are still stored in the cache.
+func skipCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipCacheKey{}, true)
+}
+
+// expiresAt converts a ttl into an absolute expiration time.
+// The zero time means that the entry never expires.
+func expiresAt(ttl time.Duration) time.Time {
+	if ttl < 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+func isExpired(expiration time.Time) bool {
+	return !expiration.IsZero() && time.Now().After(expiration)
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used
+// entries once it holds more than its maximum number of entries.
+type LRUCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	entries    map[string]*list.Element
+}
+
+type lruEntry struct {
+	key        string
+	body       []byte
+	expiration time.Time
+}
+
+// NewLRUCache returns an LRUCache holding up to maxEntries responses.
+// If maxEntries is zero or negative, the cache is unbounded.
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if isExpired(entry.expiration) {
+		c.removeElement(el)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return entry.body, true
+}
+
+// Set implements Cache.
+func (c *LRUCache) Set(key string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.body = body
+		entry.expiration = expiresAt(ttl)
+		c.ll.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.ll.PushFront(&lruEntry{key: key, body: body, expiration: expiresAt(ttl)})
+	if c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		c.removeElement(c.ll.Back())
+	}
+}
+
+// Delete implements Cache.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len returns the number of entries in the cache, including expired ones
+// that have not been evicted yet.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+func (c *LRUCache) removeElement(el *list.Element) {
+	c.ll.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key)
+}
+
+// DiskCache is a Cache that stores each response in its own file
+// inside a directory, so that it survives restarts.
+// Each file starts with the expiration time of the entry
+// (8 bytes, Unix nanoseconds, 0 for no expiration) followed by the body.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a DiskCache storing its files in dir.
+// The directory is created if it does not exist.
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if dir == "" {
+		return nil, errors.New("cache directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil || len(data) < 8 {
+		return nil, false
+	}
+	var expiration time.Time
+	if nanos := int64(binary.BigEndian.Uint64(data[:8])); nanos != 0 {
+		expiration = time.Unix(0, nanos)
+	}
+	if isExpired(expiration) {
+		c.Delete(key)
+		return nil, false
+	}
+	return data[8:], true
+}
+
+// Set implements Cache.
+// Errors writing to disk are ignored: the entry is simply not cached.
+func (c *DiskCache) Set(key string, body []byte, ttl time.Duration) {
+	var nanos int64
+	if expiration := expiresAt(ttl); !expiration.IsZero() {
+		nanos = expiration.UnixNano()
+	}
+	data := make([]byte, 8+len(body))
+	binary.BigEndian.PutUint64(data[:8], uint64(nanos))
+	copy(data[8:], body)
+
+	// write to a temporary file first so that readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, "tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.path(key))
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+}
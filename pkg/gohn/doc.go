@@ -14,7 +14,7 @@ Package GoHN is a wrapper for the Hacker News API: https://github.com/HackerNews
 
 		func main() {
 			// Instantiate a new client to retrieve data from the Hacker News API
-			hn := gohn.NewClient(nil)
+			hn, _ := gohn.NewClient()
 
 			// Use background context
 			ctx := context.Background()
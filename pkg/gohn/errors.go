@@ -1,10 +1,22 @@
 ﻿package gohn
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
 
+// ErrItemNotFound is reported when the API returns null for an item,
+// which happens for IDs that do not exist or are not available yet.
+var ErrItemNotFound = errors.New("item not found")
+
+// ErrStopFetching can be returned by an ItemProcessor to make
+// ItemsService.FetchDescendants (and FetchAllDescendants) stop fetching items.
+// The item is excluded and the items retrieved so far are returned.
+// The other methods taking an ItemProcessor, such as ItemsService.GetMany,
+// the story lists and ThreadPager, treat it like any other error.
+var ErrStopFetching = errors.New("stop fetching")
+
 type InvalidItemError struct {
 	Message string
 }
@@ -20,3 +32,41 @@ type ResponseError struct {
 func (r *ResponseError) Error() string {
 	return fmt.Sprintf("Error %d for %v", r.Response.StatusCode, r.Response.Request.URL)
 }
+
+// InvalidOptionError is returned by NewClient when an Option is misconfigured.
+type InvalidOptionError struct {
+	Option  string
+	Message string
+}
+
+func (e *InvalidOptionError) Error() string {
+	return fmt.Sprintf("invalid option %s: %v", e.Option, e.Message)
+}
+
+// ItemError reports that a single item could not be retrieved.
+type ItemError struct {
+	// ID is the ID of the item.
+	ID int
+	// Parent is the ID of the item whose kid could not be retrieved, or 0 if unknown.
+	Parent int
+	Err    error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.ID, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// ItemErrors is returned by batch operations, such as ItemsService.GetMany,
+// when some of the items could not be retrieved.
+type ItemErrors []*ItemError
+
+func (e ItemErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d items could not be retrieved, first error: %v", len(e), e[0])
+}
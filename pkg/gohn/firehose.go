@@ -0,0 +1,240 @@
+package gohn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// FirehoseOptions configures ItemsService.Firehose.
+type FirehoseOptions struct {
+	// StartAfter is the ID after which items are emitted,
+	// e.g. a checkpoint saved by a previous run (see OnCheckpoint).
+	// If zero, the firehose starts from the current max item ID.
+	StartAfter int
+	// PollInterval is the delay between two checks of the max item ID.
+	// If zero, 5 seconds is used.
+	PollInterval time.Duration
+	// BatchSize is the maximum number of items requested at once.
+	// If zero, 100 is used.
+	BatchSize int
+	// Concurrency is the number of items fetched concurrently.
+	// If zero, DefaultFetchWorkers is used.
+	Concurrency int
+	// MaxGapRetries is the number of polls during which an item that is not
+	// available yet is retried before being skipped. If zero, 10 is used.
+	MaxGapRetries int
+	// Buffer is the capacity of the returned channel.
+	// With the default of zero, the firehose waits for each item to be received
+	// before fetching more.
+	Buffer int
+	// OnCheckpoint, if not nil, is called with the highest ID such that
+	// all the items up to it have been emitted or skipped.
+	// It can be saved and passed as StartAfter to resume the firehose.
+	OnCheckpoint func(id int)
+	// OnError, if not nil, is called when the max item ID cannot be retrieved
+	// and with an *ItemError for each skipped item.
+	OnError func(error)
+}
+
+// Firehose follows every new item (story, comment, job, poll...) as it is created.
+// It polls the max item ID and emits on the returned channel every item between
+// the last one seen and the current max, in ID order.
+// Items that are not available yet (the API returns null for them for a short while)
+// are retried at the following polls and emitted as soon as they are available,
+// hence possibly out of order. The max item ID and the retried items are never
+// served from the Cache of the Client.
+// The channel is closed when ctx is done.
+// A nil opts is equivalent to the zero FirehoseOptions.
+func (s *ItemsService) Firehose(ctx context.Context, opts *FirehoseOptions) (<-chan *Item, error) {
+	if opts == nil {
+		opts = &FirehoseOptions{}
+	}
+	if opts.StartAfter < 0 || opts.BatchSize < 0 || opts.MaxGapRetries < 0 || opts.Buffer < 0 {
+		return nil, errors.New("firehose options must not be negative")
+	}
+	f := &firehose{
+		items:         s,
+		opts:          opts,
+		pollInterval:  opts.PollInterval,
+		batchSize:     opts.BatchSize,
+		maxGapRetries: opts.MaxGapRetries,
+		gaps:          map[int]int{},
+		out:           make(chan *Item, opts.Buffer),
+	}
+	if f.pollInterval <= 0 {
+		f.pollInterval = 5 * time.Second
+	}
+	if f.batchSize == 0 {
+		f.batchSize = 100
+	}
+	if f.maxGapRetries == 0 {
+		f.maxGapRetries = 10
+	}
+
+	f.last = opts.StartAfter
+	if f.last == 0 {
+		maxID, err := s.GetMaxID(skipCache(ctx))
+		if err != nil {
+			return nil, err
+		}
+		if maxID == nil {
+			return nil, errors.New("max item ID is null")
+		}
+		f.last = *maxID
+	}
+	f.checkpoint = f.last
+
+	go f.run(ctx)
+	return f.out, nil
+}
+
+// firehose holds the state of a running ItemsService.Firehose.
+type firehose struct {
+	items         *ItemsService
+	opts          *FirehoseOptions
+	pollInterval  time.Duration
+	batchSize     int
+	maxGapRetries int
+	// last is the highest ID requested so far.
+	last int
+	// checkpoint is the last checkpoint reported.
+	checkpoint int
+	// gaps maps the IDs of the items not available yet to the number of attempts.
+	gaps map[int]int
+	out  chan *Item
+}
+
+func (f *firehose) run(ctx context.Context) {
+	defer close(f.out)
+	for {
+		if !f.retryGaps(ctx) || !f.fetchNew(ctx) {
+			return
+		}
+		if sleep(ctx, f.pollInterval) != nil {
+			return
+		}
+	}
+}
+
+// retryGaps fetches again the items that were not available.
+// It returns false if ctx is done.
+func (f *firehose) retryGaps(ctx context.Context) bool {
+	if len(f.gaps) == 0 {
+		return true
+	}
+	ids := make([]int, 0, len(f.gaps))
+	for id := range f.gaps {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for len(ids) > 0 {
+		n := f.batchSize
+		if n > len(ids) {
+			n = len(ids)
+		}
+		// the missing items may have been created since they were cached
+		if !f.fetch(skipCache(ctx), ids[:n]) {
+			return false
+		}
+		ids = ids[n:]
+	}
+	f.updateCheckpoint()
+	return true
+}
+
+// fetchNew fetches the items created since the last poll.
+// It returns false if ctx is done.
+func (f *firehose) fetchNew(ctx context.Context) bool {
+	// the max item ID must not be served from the Cache of the Client,
+	// which may keep it for longer than the poll interval
+	maxID, err := f.items.GetMaxID(skipCache(ctx))
+	if err != nil || maxID == nil {
+		if ctx.Err() != nil {
+			return false
+		}
+		if err == nil {
+			err = errors.New("max item ID is null")
+		}
+		f.reportError(err)
+		return true
+	}
+	for f.last < *maxID {
+		end := f.last + f.batchSize
+		if end > *maxID {
+			end = *maxID
+		}
+		ids := make([]int, 0, end-f.last)
+		for id := f.last + 1; id <= end; id++ {
+			ids = append(ids, id)
+		}
+		if !f.fetch(ctx, ids) {
+			return false
+		}
+		f.last = end
+		f.updateCheckpoint()
+	}
+	return true
+}
+
+// fetch retrieves ids and emits the available items in order.
+// The other ones are recorded as gaps, or skipped once they
+// have been retried too many times. It returns false if ctx is done.
+func (f *firehose) fetch(ctx context.Context, ids []int) bool {
+	items, err := f.items.GetMany(ctx, ids, &GetManyOptions{Concurrency: f.opts.Concurrency})
+	if err != nil && ctx.Err() != nil {
+		return false
+	}
+	errs := map[int]error{}
+	var itemErrs ItemErrors
+	if errors.As(err, &itemErrs) {
+		for _, e := range itemErrs {
+			errs[e.ID] = e.Err
+		}
+	}
+	for i, id := range ids {
+		if items != nil && items[i] != nil {
+			delete(f.gaps, id)
+			select {
+			case f.out <- items[i]:
+			case <-ctx.Done():
+				return false
+			}
+			continue
+		}
+		f.gaps[id]++
+		if f.gaps[id] > f.maxGapRetries {
+			delete(f.gaps, id)
+			itemErr := errs[id]
+			if itemErr == nil {
+				itemErr = ErrItemNotFound
+			}
+			f.reportError(&ItemError{ID: id, Err: fmt.Errorf("skipped after %d attempts: %w", f.maxGapRetries+1, itemErr)})
+		}
+	}
+	return true
+}
+
+// updateCheckpoint reports the new checkpoint, if it moved forward.
+func (f *firehose) updateCheckpoint() {
+	checkpoint := f.last
+	for id := range f.gaps {
+		if id-1 < checkpoint {
+			checkpoint = id - 1
+		}
+	}
+	if checkpoint > f.checkpoint {
+		f.checkpoint = checkpoint
+		if f.opts.OnCheckpoint != nil {
+			f.opts.OnCheckpoint(checkpoint)
+		}
+	}
+}
+
+func (f *firehose) reportError(err error) {
+	if f.opts.OnError != nil {
+		f.opts.OnError(err)
+	}
+}
@@ -1,6 +1,7 @@
 package gohn
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,6 +19,9 @@ const (
 )
 
 // Client manages communication with the Hacker News API.
+// BaseURL and UserAgent should be set with WithBaseURL and WithUserAgent,
+// which validate them when the Client is created. NewRequest checks again
+// that BaseURL has a trailing slash, in case it is changed afterwards.
 type Client struct {
 	httpClient *http.Client
 	BaseURL    *url.URL
@@ -26,6 +31,16 @@ type Client struct {
 	Users      *UsersService
 	Updates    *UpdatesService
 	UserAgent  string
+
+	cache       Cache
+	cachePolicy CachePolicy
+	retryPolicy RetryPolicy
+	rateLimiter RateLimiter
+	inFlight    semaphore
+	logger      Logger
+	metrics     func(RequestMetrics)
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
 }
 
 type service struct {
@@ -38,19 +53,57 @@ func (c *Client) GetHTTPClient() *http.Client {
 }
 
 // NewClient returns a new Client that will be used to make requests to the Hacker News API.
-// If a nil httpClient is provided, http.Client will be used.
-func NewClient(httpClient *http.Client) (*Client, error) {
-	if httpClient == nil {
+// Without options, the Client uses a new http.Client with a 10 seconds timeout,
+// BASE_URL and USER_AGENT. The options are validated up front
+// and an *InvalidOptionError is returned for the first invalid one.
+func NewClient(opts ...Option) (*Client, error) {
+	cfg := clientConfig{userAgent: USER_AGENT}
+	for _, opt := range opts {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	var httpClient *http.Client
+	if cfg.httpClient == nil {
 		httpClient = &http.Client{
 			Timeout: time.Second * 10,
 		}
+	} else {
+		// copy the client so that the caller's one is not modified below
+		clientCopy := *cfg.httpClient
+		httpClient = &clientCopy
+	}
+	if cfg.timeout > 0 {
+		httpClient.Timeout = cfg.timeout
+	}
+	if cfg.transport != nil {
+		httpClient.Transport = cfg.transport
 	}
 
-	baseURL, err := url.Parse(BASE_URL)
-	if err != nil {
-		return nil, err
+	baseURL := cfg.baseURL
+	if baseURL == nil {
+		var err error
+		baseURL, err = url.Parse(BASE_URL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := Client{
+		httpClient:  httpClient,
+		BaseURL:     baseURL,
+		UserAgent:   cfg.userAgent,
+		cache:       cfg.cache,
+		cachePolicy: cfg.cachePolicy,
+		retryPolicy: cfg.retryPolicy,
+		rateLimiter: cfg.rateLimiter,
+		logger:      cfg.logger,
+		metrics:     cfg.metrics,
+	}
+	if cfg.maxInFlight > 0 {
+		c.inFlight = make(semaphore, cfg.maxInFlight)
 	}
-	c := Client{httpClient: httpClient, BaseURL: baseURL, UserAgent: USER_AGENT}
 	c.common.client = &c
 	c.Items = (*ItemsService)(&c.common)
 	c.Stories = (*StoriesService)(&c.common)
@@ -86,9 +139,103 @@ func (c *Client) NewRequest(method, path string) (*http.Request, error) {
 // The Hacker News API returns JSON which is decoded and
 // stored in the value pointed to by v, or returned as
 // an error if an API error has occurred.
+// Each attempt waits for the RateLimiter of the Client and for a free
+// slot when the number of requests in flight is capped (see WithMaxConcurrency).
+// Failed requests are retried according to the RetryPolicy of the Client, if any.
+// If the Client has a Cache, GET requests are served from it when possible,
+// unless the data is known to have changed (e.g. for UpdatesService.Watch).
+// In that case, the returned response has no body and
+// its "X-From-Cache" header is set to "1".
 func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*http.Response, error) {
+	metrics := RequestMetrics{Method: req.Method, URL: req.URL.String()}
+	start := time.Now()
+
+	resp, err := c.do(ctx, req, v, &metrics)
+
+	if err != nil && c.logger != nil {
+		c.logger.Printf("gohn: %s %s failed: %v", req.Method, req.URL, err)
+	}
+	if c.metrics != nil {
+		metrics.Duration = time.Since(start)
+		metrics.Err = err
+		if resp != nil {
+			metrics.StatusCode = resp.StatusCode
+		}
+		c.metrics(metrics)
+	}
+	return resp, err
+}
+
+func (c *Client) do(ctx context.Context, req *http.Request, v any, metrics *RequestMetrics) (*http.Response, error) {
 	req = req.WithContext(ctx)
 
+	cacheable := c.cache != nil && req.Method == http.MethodGet
+	cacheKey := req.URL.String()
+	if cacheable && ctx.Value(skipCacheKey{}) == nil {
+		if body, ok := c.cache.Get(cacheKey); ok {
+			c.cacheHits.Add(1)
+			metrics.FromCache = true
+			return cachedResponse(req, body), decodeBody(body, v)
+		}
+		c.cacheMisses.Add(1)
+	}
+
+	resp, body, err := c.send(ctx, req, metrics)
+	if err != nil {
+		return resp, err
+	}
+
+	if cacheable && !isNullBody(body) {
+		path := strings.TrimPrefix(req.URL.Path, c.BaseURL.Path)
+		if ttl := c.cachePolicy(path, body); ttl != 0 {
+			c.cache.Set(cacheKey, body, ttl)
+		}
+	}
+
+	return resp, decodeBody(body, v)
+}
+
+// send performs req, retrying it according to the RetryPolicy of the Client,
+// and returns the response together with its body.
+// The number of attempts is recorded in metrics.
+func (c *Client) send(ctx context.Context, req *http.Request, metrics *RequestMetrics) (*http.Response, []byte, error) {
+	policy := c.retryPolicy
+	for attempt := 1; ; attempt++ {
+		metrics.Attempts = attempt
+		resp, body, err := c.attempt(ctx, req)
+		if err == nil || attempt >= policy.MaxAttempts || !policy.shouldRetry(err) {
+			return resp, body, err
+		}
+		wait := policy.backoff(attempt, resp)
+		if c.logger != nil {
+			c.logger.Printf("gohn: %s %s failed (attempt %d), retrying in %v: %v", req.Method, req.URL, attempt, wait, err)
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(RetryAttempt{Request: req, Attempt: attempt, Response: resp, Err: err, Wait: wait})
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, wait); err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+// attempt performs req once and reads the whole response body.
+func (c *Client) attempt(ctx context.Context, req *http.Request) (*http.Response, []byte, error) {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+	if c.inFlight != nil {
+		if err := c.inFlight.acquire(ctx); err != nil {
+			return nil, nil, err
+		}
+		defer c.inFlight.release()
+	}
+
 	resp, err := c.httpClient.Do(req)
 
 	if err != nil {
@@ -96,33 +243,73 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v any) (*http.Respon
 		case <-ctx.Done():
 			// If the context was canceled, return the context's error,
 			// which may be more useful than the underlying error.
-			return nil, ctx.Err()
+			return nil, nil, ctx.Err()
 		default:
 		}
-		return nil, err
+		return nil, nil, err
 	}
 
 	err = CheckResponse(resp)
 	if err != nil {
-		return resp, err
+		return resp, nil, err
 	}
 
 	defer resp.Body.Close()
 
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+	return resp, body, nil
+}
+
+// CacheStats returns the number of cache hits and misses of the Client.
+// Both are zero if the Client has no Cache.
+func (c *Client) CacheStats() CacheStats {
+	return CacheStats{
+		Hits:   c.cacheHits.Load(),
+		Misses: c.cacheMisses.Load(),
+	}
+}
+
+// decodeBody stores body in v. If v is an io.Writer, body is written to it,
+// otherwise it is decoded as JSON.
+func decodeBody(body []byte, v any) error {
 	switch v := v.(type) {
 	case nil:
 	case io.Writer:
-		_, err = io.Copy(v, resp.Body)
+		_, err := v.Write(body)
+		return err
 	default:
-		decErr := json.NewDecoder(resp.Body).Decode(v)
-		if decErr == io.EOF {
-			decErr = nil // ignore EOF errors caused by empty response body
-		}
-		if decErr != nil {
-			err = decErr
+		if len(bytes.TrimSpace(body)) == 0 {
+			return nil // ignore empty response body
 		}
+		return json.Unmarshal(body, v)
+	}
+	return nil
+}
+
+// isNullBody reports whether body is empty or the JSON null,
+// which the API returns for items that do not exist (yet).
+func isNullBody(body []byte) bool {
+	body = bytes.TrimSpace(body)
+	return len(body) == 0 || bytes.Equal(body, []byte("null"))
+}
+
+func cachedResponse(req *http.Request, body []byte) *http.Response {
+	header := make(http.Header)
+	header.Set("X-From-Cache", "1")
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          http.NoBody,
+		ContentLength: int64(len(body)),
+		Request:       req,
 	}
-	return resp, err
 }
 
 // CheckResponse checks the API response for errors, and returns them if present.
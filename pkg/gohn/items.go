@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -38,10 +39,56 @@ type Item struct {
 	Descendants *int    `json:"descendants,omitempty"`
 }
 
-// ItemProcessor is used by ItemsService.Get and ItemsService.FetchAllKids
+// ItemProcessor is used by ItemsService.FetchAllDescendants and ItemsService.FetchDescendants
 // to process items after they are retrieved.
-// The package itemprocessor provides some common implementations.
-type ItemProcessor func(*Item, *sync.WaitGroup) (bool, error)
+// If it returns an error, the item is excluded from the results and the boolean
+// reports whether its kids should be excluded as well.
+// If the error is ErrStopFetching, FetchDescendants also stops fetching items
+// (the other methods only exclude the item).
+// It may be called concurrently from several goroutines.
+// The package processors provides some common implementations.
+type ItemProcessor func(*Item) (bool, error)
+
+// DefaultFetchWorkers is the number of items fetched concurrently
+// by ItemsService.FetchDescendants when FetchOptions.Workers is not set.
+const DefaultFetchWorkers = 10
+
+// FetchOptions configures ItemsService.FetchDescendants.
+type FetchOptions struct {
+	// Workers is the number of items fetched concurrently.
+	// If zero, DefaultFetchWorkers is used.
+	Workers int
+	// MaxDepth limits how deep in the tree descendants are fetched:
+	// 1 fetches only the kids of the item, 2 their kids too, and so on.
+	// If zero, there is no limit.
+	MaxDepth int
+	// MaxItems limits the number of items returned.
+	// If zero, there is no limit.
+	MaxItems int
+	// Processor, if not nil, is applied to each retrieved item. See ItemProcessor.
+	Processor ItemProcessor
+	// Strict makes FetchDescendants stop at the first item that
+	// cannot be retrieved and return its *ItemError.
+	Strict bool
+}
+
+// FetchResult is returned by ItemsService.FetchDescendants.
+type FetchResult struct {
+	// Items holds the retrieved items indexed by their ID.
+	Items ItemsIndex
+	// Failed lists the items that could not be retrieved, sorted by ID.
+	// Their descendants are missing from Items: fetching them again
+	// with FetchDescendants completes the tree.
+	Failed []*ItemError
+	// Stopped reports whether the processor stopped the fetching
+	// by returning ErrStopFetching.
+	Stopped bool
+}
+
+// Complete reports whether every reachable item was retrieved.
+func (r *FetchResult) Complete() bool {
+	return len(r.Failed) == 0
+}
 
 // Get returns an Item given an ID.
 func (s *ItemsService) Get(ctx context.Context, id int) (*Item, error) {
@@ -59,6 +106,96 @@ func (s *ItemsService) Get(ctx context.Context, id int) (*Item, error) {
 	return item, nil
 }
 
+// GetManyOptions configures ItemsService.GetMany.
+type GetManyOptions struct {
+	// Concurrency is the number of items fetched concurrently.
+	// If zero, DefaultFetchWorkers is used.
+	Concurrency int
+	// Processor, if not nil, is applied to each retrieved item.
+	// Items for which it returns an error are nil in the returned slice,
+	// like the items that cannot be retrieved, but they are not reported
+	// in the returned ItemErrors.
+	Processor ItemProcessor
+}
+
+// GetMany returns the Items with the given IDs, in the same order as ids.
+// Repeated IDs are retrieved only once and share the same *Item.
+// The items that cannot be retrieved, including those for which the API
+// returns null, are nil in the returned slice and are reported
+// in the returned error, of type ItemErrors.
+// If ctx is done before all the items are retrieved, only ctx.Err() is returned.
+// A nil opts is equivalent to the zero GetManyOptions.
+func (s *ItemsService) GetMany(ctx context.Context, ids []int, opts *GetManyOptions) ([]*Item, error) {
+	if opts == nil {
+		opts = &GetManyOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFetchWorkers
+	}
+
+	var unique []int
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	results := make([]fetchResult, len(unique))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	if concurrency > len(unique) {
+		concurrency = len(unique)
+	}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indexes {
+				results[idx] = s.fetchAndProcess(ctx, fetchJob{id: unique[idx]}, opts.Processor)
+			}
+		}()
+	}
+L:
+	for i := range unique {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break L
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[int]*Item, len(unique))
+	var errs ItemErrors
+	for _, res := range results {
+		if res.err == nil && res.item == nil {
+			res.err = ErrItemNotFound
+		}
+		if res.err != nil {
+			errs = append(errs, &ItemError{ID: res.id, Err: res.err})
+			continue
+		}
+		if !res.excluded {
+			byID[res.id] = res.item
+		}
+	}
+	items := make([]*Item, len(ids))
+	for i, id := range ids {
+		items[i] = byID[id]
+	}
+	if len(errs) > 0 {
+		return items, errs
+	}
+	return items, nil
+}
+
 // GetIDsFromURL returns a slice of Items' IDs for the given URL.
 func (s *ItemsService) GetIDsFromURL(ctx context.Context, url string) ([]*int, error) {
 	req, err := s.client.NewRequest("GET", url)
@@ -81,120 +218,175 @@ func (s *ItemsService) GetIDsFromURL(ctx context.Context, url string) ([]*int, e
 // If the ItemProcessor returns an error, the item will not be added to the map.
 // Its kids will be added to the queue only if the ItemProcessors returns false, together with the error.
 // For more information on the ItemProcessor, check the gohn/processors package.
+// Items that cannot be retrieved are skipped: use FetchDescendants
+// to know which ones failed.
 func (s *ItemsService) FetchAllDescendants(ctx context.Context, item *Item, fn ItemProcessor) (ItemsIndex, error) {
+	res, err := s.FetchDescendants(ctx, item, &FetchOptions{Processor: fn})
+	if err != nil {
+		return nil, err
+	}
+	return res.Items, nil
+}
+
+// fetchJob is an item to be retrieved by a FetchDescendants worker.
+type fetchJob struct {
+	id     int
+	parent int
+	depth  int
+}
+
+// fetchResult is the outcome of a fetchJob.
+type fetchResult struct {
+	fetchJob
+	item *Item
+	err  error
+	// excluded reports whether the processor excluded the item.
+	excluded bool
+	// skipKids reports whether the kids of the item must not be fetched.
+	skipKids bool
+	// stop reports whether the processor returned ErrStopFetching.
+	stop bool
+}
+
+// FetchDescendants retrieves the descendants of a given Item,
+// like FetchAllDescendants, using a fixed pool of workers.
+// The tree is visited breadth first and the fetching ends when
+// every reachable kid has been processed, one of the limits in opts is reached
+// or the processor returns ErrStopFetching, regardless of the value of item.Descendants.
+// Items that cannot be retrieved (including those for which the API returns null)
+// are reported in FetchResult.Failed. In strict mode, the partial result
+// is returned together with the *ItemError of the first failure.
+// A nil opts is equivalent to the zero FetchOptions.
+func (s *ItemsService) FetchDescendants(ctx context.Context, item *Item, opts *FetchOptions) (*FetchResult, error) {
 	if item == nil {
 		return nil, errors.New("item is nil")
 	}
 	if item.Kids == nil {
 		return nil, errors.New("item has no kids")
 	}
+	if opts == nil {
+		opts = &FetchOptions{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = DefaultFetchWorkers
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	jobs := make(chan fetchJob)
+	results := make(chan fetchResult)
 	var wg sync.WaitGroup
-	// channel of items to be added to the map
-	var commentsChan chan *Item
-	// channel of IDs to be retrieved
-	// kids found in the commentsChan will be added to this channel
-	// buffered so that initializing the queue doesn't block
-	var kidsQueue chan int
-	// channel to signaling that the processing is done
-	done := make(chan struct{})
-	// number of items to fetch and process
-	var commentsNumToFetch int
-	// map of items to return
-	var mapCommentById ItemsIndex
-
-	if item.Descendants != nil && *item.Descendants > 0 {
-		commentsNumToFetch = *item.Descendants
-		mapCommentById = make(ItemsIndex, commentsNumToFetch)
-		commentsChan = make(chan *Item, commentsNumToFetch)
-		kidsQueue = make(chan int, commentsNumToFetch)
-	} else {
-		commentsNumToFetch = len(*item.Kids)
-		mapCommentById = make(ItemsIndex)
-		commentsChan = make(chan *Item)
-		kidsQueue = make(chan int, commentsNumToFetch)
-	}
-
-	wg.Add(len(*item.Kids))
-
-	// the following variables are used to signal the start of the processing
-	start := make(chan struct{})
-	var startOnce sync.Once
-
-	// initialize kidsQueue so that the fetching in the for loop can start
-	go func() {
-		for _, kid := range *item.Kids {
-			kidsQueue <- kid
-		}
-	}()
-
-	// goroutine to close the done channel when all the items are fetched and processed
-	go func() {
-		// wait for the first kid to be in the queue
-		// in this way, we don't close the done channel before the processing has started
-		<-start
-		// the first kid has been added to the queue
-		// it is safe now to start waiting
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				res := s.fetchAndProcess(ctx, job, opts.Processor)
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	// stop terminates the workers and waits for them to return
+	stop := func() {
+		close(jobs)
+		cancel()
 		wg.Wait()
-		close(done)
-	}()
+	}
+
+	mapCommentById := make(ItemsIndex)
+	var failed []*ItemError
+	// IDs already queued, so that an item is never fetched twice
+	seen := map[int]bool{}
+	var queue []fetchJob
+	enqueue := func(parent int, kids []int, depth int) {
+		if opts.MaxDepth > 0 && depth > opts.MaxDepth {
+			return
+		}
+		for _, kid := range kids {
+			if !seen[kid] {
+				seen[kid] = true
+				queue = append(queue, fetchJob{id: kid, parent: parent, depth: depth})
+			}
+		}
+	}
+	var rootID int
+	if item.ID != nil {
+		rootID = *item.ID
+		seen[rootID] = true
+	}
+	enqueue(rootID, *item.Kids, 1)
+
+	inFlight := 0
+	stopped := false
 L:
-	for {
+	for len(queue) > 0 || inFlight > 0 {
+		if opts.MaxItems > 0 && len(mapCommentById) >= opts.MaxItems {
+			break
+		}
+		// a nil channel blocks forever, so no job is sent when the queue is empty
+		var jobsChan chan fetchJob
+		var next fetchJob
+		if len(queue) > 0 {
+			jobsChan = jobs
+			next = queue[0]
+		}
 		select {
 		case <-ctx.Done():
+			stop()
 			return nil, ctx.Err()
-		// fetch the item and send it to commentsChan
-		case currentId := <-kidsQueue:
-			// once the first kid has been added to the queue, signal the start of the processing
-			// this is done to avoid closing the done channel before the processing has started
-			startOnce.Do(func() {
-				close(start)
-			})
-			go func(wg *sync.WaitGroup, currentId int) {
-				it, err := s.Get(ctx, currentId)
-				if err != nil {
-					// TODO: add better error handling
-					wg.Done()
-					return
-				}
-				if fn != nil {
-					excludeKids, err := fn(it, wg)
-					if err != nil && excludeKids {
-						// TODO: add better error handling
-						wg.Done()
-						return
-					} else if err != nil && !excludeKids {
-						if it.Kids != nil {
-							wg.Add(len(*it.Kids))
-							for _, kid := range *it.Kids {
-								kidsQueue <- kid
-							}
-						}
-						wg.Done()
-						return
-					}
-				}
-				commentsChan <- it
-			}(&wg, currentId)
-		// add the item to the map and, if it has any kid,
-		// add their IDs to the queue so that they can be fetched
-		case comment := <-commentsChan:
-			if comment.ID != nil {
-				mapCommentById[*comment.ID] = comment
-				if comment.Kids != nil && len(*comment.Kids) > 0 {
-					wg.Add(len(*comment.Kids))
-					go func(comment *Item) {
-						for _, kid := range *comment.Kids {
-							kidsQueue <- kid
-						}
-					}(comment)
+		case jobsChan <- next:
+			queue = queue[1:]
+			inFlight++
+		case res := <-results:
+			inFlight--
+			if res.err == nil && res.item == nil {
+				res.err = ErrItemNotFound
+			}
+			if res.err != nil {
+				itemErr := &ItemError{ID: res.id, Parent: res.parent, Err: res.err}
+				failed = append(failed, itemErr)
+				if opts.Strict {
+					stop()
+					return &FetchResult{Items: mapCommentById, Failed: failed}, itemErr
 				}
+				continue
+			}
+			if res.stop {
+				stopped = true
+				break L
+			}
+			if !res.excluded && res.item.ID != nil {
+				mapCommentById[*res.item.ID] = res.item
+			}
+			if !res.skipKids && res.item.Kids != nil {
+				enqueue(res.id, *res.item.Kids, res.depth+1)
 			}
-			wg.Done()
-		case <-done:
-			break L
 		}
 	}
-	return mapCommentById, nil
+	stop()
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].ID < failed[j].ID
+	})
+	return &FetchResult{Items: mapCommentById, Failed: failed, Stopped: stopped}, nil
+}
+
+// fetchAndProcess retrieves the item of job and applies fn to it.
+func (s *ItemsService) fetchAndProcess(ctx context.Context, job fetchJob, fn ItemProcessor) fetchResult {
+	res := fetchResult{fetchJob: job}
+	res.item, res.err = s.Get(ctx, job.id)
+	if res.err != nil || res.item == nil || fn == nil {
+		return res
+	}
+	if skipKids, err := fn(res.item); err != nil {
+		res.excluded = true
+		res.skipKids = skipKids
+		res.stop = errors.Is(err, ErrStopFetching)
+	}
+	return res
 }
 
 // GetMaxID returns the ID of the most recent item.
@@ -213,23 +405,21 @@ func (s *ItemsService) GetMaxID(ctx context.Context) (*int, error) {
 }
 
 // GetStoryIdFromComment returns the ID of the story for a given comment.
+// It returns nil if the root of the thread is not a story (e.g. a poll).
+// See GetAncestors.
 func (s *ItemsService) GetStoryIdFromComment(ctx context.Context, item *Item) (*int, error) {
 	if item == nil {
 		return nil, InvalidItemError{Message: "item is nil"}
 	}
-	if *item.Type != "comment" {
+	if !item.Is(ItemTypeComment) {
 		return nil, InvalidItemError{Message: "item is not a comment"}
 	}
-	var storyId *int
-	for {
-		if *item.Type == "story" {
-			storyId = item.ID
-			break
-		}
-		if item.Parent == nil {
-			break
-		}
-		item, _ = s.Get(ctx, *item.Parent)
+	root, err := s.GetRoot(ctx, item)
+	if err != nil {
+		return nil, err
+	}
+	if !root.Is(ItemTypeStory) {
+		return nil, nil
 	}
-	return storyId, nil
+	return root.ID, nil
 }
@@ -0,0 +1,376 @@
+package gohn
+
+import (
+	"fmt"
+	"time"
+)
+
+// ItemType is the type of an Item.
+type ItemType string
+
+// The item types returned by the Hacker News API.
+const (
+	ItemTypeStory   ItemType = "story"
+	ItemTypeComment ItemType = "comment"
+	ItemTypeJob     ItemType = "job"
+	ItemTypePoll    ItemType = "poll"
+	ItemTypePollOpt ItemType = "pollopt"
+)
+
+// IsValid reports whether t is one of the known item types.
+func (t ItemType) IsValid() bool {
+	switch t {
+	case ItemTypeStory, ItemTypeComment, ItemTypeJob, ItemTypePoll, ItemTypePollOpt:
+		return true
+	}
+	return false
+}
+
+// Kind returns the type of the item, or an empty ItemType
+// if the item is nil or has no type.
+func (i *Item) Kind() ItemType {
+	if i == nil || i.Type == nil {
+		return ""
+	}
+	return ItemType(*i.Type)
+}
+
+// Is reports whether the item is of type t.
+func (i *Item) Is(t ItemType) bool {
+	return i.Kind() == t
+}
+
+// GetID returns the ID of the item, or 0 if it is not set.
+func (i *Item) GetID() int {
+	if i == nil || i.ID == nil {
+		return 0
+	}
+	return *i.ID
+}
+
+// GetBy returns the username of the author of the item, or "" if it is not set.
+func (i *Item) GetBy() string {
+	if i == nil || i.By == nil {
+		return ""
+	}
+	return *i.By
+}
+
+// GetTime returns the creation time of the item,
+// or the zero time.Time if it is not set.
+func (i *Item) GetTime() time.Time {
+	if i == nil || i.Time == nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(*i.Time), 0)
+}
+
+// GetText returns the HTML text of the item, or "" if it is not set.
+func (i *Item) GetText() string {
+	if i == nil || i.Text == nil {
+		return ""
+	}
+	return *i.Text
+}
+
+// GetParent returns the ID of the parent of the item, or 0 if it is not set.
+func (i *Item) GetParent() int {
+	if i == nil || i.Parent == nil {
+		return 0
+	}
+	return *i.Parent
+}
+
+// GetPoll returns the ID of the poll of a pollopt, or 0 if it is not set.
+func (i *Item) GetPoll() int {
+	if i == nil || i.Poll == nil {
+		return 0
+	}
+	return *i.Poll
+}
+
+// GetKids returns the IDs of the kids of the item, or nil if it has none.
+func (i *Item) GetKids() []int {
+	if i == nil || i.Kids == nil {
+		return nil
+	}
+	return *i.Kids
+}
+
+// GetURL returns the URL of the item, or "" if it is not set.
+func (i *Item) GetURL() string {
+	if i == nil || i.URL == nil {
+		return ""
+	}
+	return *i.URL
+}
+
+// GetScore returns the score of the item, or 0 if it is not set.
+func (i *Item) GetScore() int {
+	if i == nil || i.Score == nil {
+		return 0
+	}
+	return *i.Score
+}
+
+// GetTitle returns the title of the item, or "" if it is not set.
+func (i *Item) GetTitle() string {
+	if i == nil || i.Title == nil {
+		return ""
+	}
+	return *i.Title
+}
+
+// GetParts returns the IDs of the options of a poll, or nil if it has none.
+func (i *Item) GetParts() []int {
+	if i == nil || i.Parts == nil {
+		return nil
+	}
+	return *i.Parts
+}
+
+// GetDescendants returns the number of comments of the item, or 0 if it is not set.
+func (i *Item) GetDescendants() int {
+	if i == nil || i.Descendants == nil {
+		return 0
+	}
+	return *i.Descendants
+}
+
+// IsDeleted reports whether the item has been deleted.
+func (i *Item) IsDeleted() bool {
+	return i != nil && i.Deleted != nil && *i.Deleted
+}
+
+// IsDead reports whether the item is dead.
+func (i *Item) IsDead() bool {
+	return i != nil && i.Dead != nil && *i.Dead
+}
+
+// StoryItem is a view of an Item of type story.
+// Raw is the Item it was created from.
+type StoryItem struct {
+	Raw         *Item
+	ID          int
+	By          string
+	Time        time.Time
+	Title       string
+	URL         string
+	Text        string
+	Score       int
+	Descendants int
+	Kids        []int
+	Deleted     bool
+	Dead        bool
+}
+
+// NewStoryItem returns a StoryItem for item.
+// It returns an InvalidItemError if item has no ID or is not a story.
+func NewStoryItem(item *Item) (*StoryItem, error) {
+	if err := checkItem(item, ItemTypeStory); err != nil {
+		return nil, err
+	}
+	return &StoryItem{
+		Raw:         item,
+		ID:          *item.ID,
+		By:          item.GetBy(),
+		Time:        item.GetTime(),
+		Title:       item.GetTitle(),
+		URL:         item.GetURL(),
+		Text:        item.GetText(),
+		Score:       item.GetScore(),
+		Descendants: item.GetDescendants(),
+		Kids:        item.GetKids(),
+		Deleted:     item.IsDeleted(),
+		Dead:        item.IsDead(),
+	}, nil
+}
+
+// CommentItem is a view of an Item of type comment.
+// Raw is the Item it was created from.
+type CommentItem struct {
+	Raw     *Item
+	ID      int
+	By      string
+	Time    time.Time
+	Text    string
+	Parent  int
+	Kids    []int
+	Deleted bool
+	Dead    bool
+}
+
+// NewCommentItem returns a CommentItem for item.
+// It returns an InvalidItemError if item has no ID, is not a comment or has no parent.
+func NewCommentItem(item *Item) (*CommentItem, error) {
+	if err := checkItem(item, ItemTypeComment); err != nil {
+		return nil, err
+	}
+	if item.Parent == nil {
+		return nil, InvalidItemError{Message: fmt.Sprintf("comment %d has no parent", *item.ID)}
+	}
+	return &CommentItem{
+		Raw:     item,
+		ID:      *item.ID,
+		By:      item.GetBy(),
+		Time:    item.GetTime(),
+		Text:    item.GetText(),
+		Parent:  *item.Parent,
+		Kids:    item.GetKids(),
+		Deleted: item.IsDeleted(),
+		Dead:    item.IsDead(),
+	}, nil
+}
+
+// JobItem is a view of an Item of type job.
+// Raw is the Item it was created from.
+type JobItem struct {
+	Raw     *Item
+	ID      int
+	By      string
+	Time    time.Time
+	Title   string
+	URL     string
+	Text    string
+	Score   int
+	Deleted bool
+	Dead    bool
+}
+
+// NewJobItem returns a JobItem for item.
+// It returns an InvalidItemError if item has no ID or is not a job.
+func NewJobItem(item *Item) (*JobItem, error) {
+	if err := checkItem(item, ItemTypeJob); err != nil {
+		return nil, err
+	}
+	return &JobItem{
+		Raw:     item,
+		ID:      *item.ID,
+		By:      item.GetBy(),
+		Time:    item.GetTime(),
+		Title:   item.GetTitle(),
+		URL:     item.GetURL(),
+		Text:    item.GetText(),
+		Score:   item.GetScore(),
+		Deleted: item.IsDeleted(),
+		Dead:    item.IsDead(),
+	}, nil
+}
+
+// PollItem is a view of an Item of type poll.
+// Raw is the Item it was created from.
+type PollItem struct {
+	Raw         *Item
+	ID          int
+	By          string
+	Time        time.Time
+	Title       string
+	Text        string
+	Score       int
+	Descendants int
+	Kids        []int
+	// Parts holds the IDs of the options of the poll, in order.
+	Parts   []int
+	Deleted bool
+	Dead    bool
+}
+
+// NewPollItem returns a PollItem for item.
+// It returns an InvalidItemError if item has no ID or is not a poll.
+func NewPollItem(item *Item) (*PollItem, error) {
+	if err := checkItem(item, ItemTypePoll); err != nil {
+		return nil, err
+	}
+	return &PollItem{
+		Raw:         item,
+		ID:          *item.ID,
+		By:          item.GetBy(),
+		Time:        item.GetTime(),
+		Title:       item.GetTitle(),
+		Text:        item.GetText(),
+		Score:       item.GetScore(),
+		Descendants: item.GetDescendants(),
+		Kids:        item.GetKids(),
+		Parts:       item.GetParts(),
+		Deleted:     item.IsDeleted(),
+		Dead:        item.IsDead(),
+	}, nil
+}
+
+// PollOptItem is a view of an Item of type pollopt, i.e. an option of a poll.
+// Raw is the Item it was created from.
+type PollOptItem struct {
+	Raw  *Item
+	ID   int
+	By   string
+	Time time.Time
+	Text string
+	// Poll is the ID of the poll the option belongs to.
+	Poll    int
+	Score   int
+	Deleted bool
+	Dead    bool
+}
+
+// NewPollOptItem returns a PollOptItem for item.
+// It returns an InvalidItemError if item has no ID, is not a pollopt or has no poll.
+func NewPollOptItem(item *Item) (*PollOptItem, error) {
+	if err := checkItem(item, ItemTypePollOpt); err != nil {
+		return nil, err
+	}
+	if item.Poll == nil {
+		return nil, InvalidItemError{Message: fmt.Sprintf("pollopt %d has no poll", *item.ID)}
+	}
+	return &PollOptItem{
+		Raw:     item,
+		ID:      *item.ID,
+		By:      item.GetBy(),
+		Time:    item.GetTime(),
+		Text:    item.GetText(),
+		Poll:    *item.Poll,
+		Score:   item.GetScore(),
+		Deleted: item.IsDeleted(),
+		Dead:    item.IsDead(),
+	}, nil
+}
+
+// AsStory is a shorthand for NewStoryItem(i).
+func (i *Item) AsStory() (*StoryItem, error) {
+	return NewStoryItem(i)
+}
+
+// AsComment is a shorthand for NewCommentItem(i).
+func (i *Item) AsComment() (*CommentItem, error) {
+	return NewCommentItem(i)
+}
+
+// AsJob is a shorthand for NewJobItem(i).
+func (i *Item) AsJob() (*JobItem, error) {
+	return NewJobItem(i)
+}
+
+// AsPoll is a shorthand for NewPollItem(i).
+func (i *Item) AsPoll() (*PollItem, error) {
+	return NewPollItem(i)
+}
+
+// AsPollOpt is a shorthand for NewPollOptItem(i).
+func (i *Item) AsPollOpt() (*PollOptItem, error) {
+	return NewPollOptItem(i)
+}
+
+// checkItem returns an InvalidItemError if item is nil,
+// has no ID or is not of type want.
+func checkItem(item *Item, want ItemType) error {
+	switch {
+	case item == nil:
+		return InvalidItemError{Message: "item is nil"}
+	case item.ID == nil:
+		return InvalidItemError{Message: "item has no ID"}
+	case item.Type == nil:
+		return InvalidItemError{Message: fmt.Sprintf("item %d has no type", *item.ID)}
+	case item.Kind() != want:
+		return InvalidItemError{Message: fmt.Sprintf("item %d is a %s, not a %s", *item.ID, item.Kind(), want)}
+	}
+	return nil
+}
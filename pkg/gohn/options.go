@@ -0,0 +1,204 @@
+package gohn
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Option configures a Client. Options are passed to NewClient.
+type Option func(*clientConfig) error
+
+// clientConfig collects the settings provided through Options
+// before the Client is built.
+type clientConfig struct {
+	httpClient  *http.Client
+	timeout     time.Duration
+	transport   http.RoundTripper
+	baseURL     *url.URL
+	userAgent   string
+	cache       Cache
+	cachePolicy CachePolicy
+	retryPolicy RetryPolicy
+	rateLimiter RateLimiter
+	maxInFlight int
+	logger      Logger
+	metrics     func(RequestMetrics)
+}
+
+// Logger is used by the Client to report retries and failed requests.
+// *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// RequestMetrics describes the outcome of a call to Client.Do.
+// It is passed to the function given to WithMetrics.
+type RequestMetrics struct {
+	Method string
+	URL    string
+	// StatusCode is the status code of the last response, or 0 if none was received.
+	StatusCode int
+	// Attempts is the number of requests sent to the network (0 for cache hits).
+	Attempts int
+	// FromCache reports whether the response was served from the Cache.
+	FromCache bool
+	Duration  time.Duration
+	Err       error
+}
+
+// WithHTTPClient makes the Client send requests through httpClient.
+// It can be used to configure a proxy, for example.
+// WithTimeout and WithTransport do not modify httpClient but a copy of it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(cfg *clientConfig) error {
+		if httpClient == nil {
+			return &InvalidOptionError{Option: "WithHTTPClient", Message: "http client is nil"}
+		}
+		cfg.httpClient = httpClient
+		return nil
+	}
+}
+
+// WithTimeout sets the timeout of each request sent by the Client.
+// The default is 10 seconds.
+func WithTimeout(timeout time.Duration) Option {
+	return func(cfg *clientConfig) error {
+		if timeout <= 0 {
+			return &InvalidOptionError{Option: "WithTimeout", Message: "timeout must be positive"}
+		}
+		cfg.timeout = timeout
+		return nil
+	}
+}
+
+// WithTransport sets the http.RoundTripper used by the Client.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(cfg *clientConfig) error {
+		if transport == nil {
+			return &InvalidOptionError{Option: "WithTransport", Message: "transport is nil"}
+		}
+		cfg.transport = transport
+		return nil
+	}
+}
+
+// WithBaseURL sets the URL the paths of the requests are resolved against.
+// It must be an absolute URL with a trailing slash. The default is BASE_URL.
+func WithBaseURL(baseURL string) Option {
+	return func(cfg *clientConfig) error {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return &InvalidOptionError{Option: "WithBaseURL", Message: err.Error()}
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return &InvalidOptionError{Option: "WithBaseURL", Message: fmt.Sprintf("%q is not an absolute URL", baseURL)}
+		}
+		if !strings.HasSuffix(u.Path, "/") {
+			return &InvalidOptionError{Option: "WithBaseURL", Message: fmt.Sprintf("%q must have a trailing slash", baseURL)}
+		}
+		cfg.baseURL = u
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with each request.
+// The default is USER_AGENT.
+func WithUserAgent(userAgent string) Option {
+	return func(cfg *clientConfig) error {
+		cfg.userAgent = userAgent
+		return nil
+	}
+}
+
+// WithLogger makes the Client report retries and failed requests to logger.
+func WithLogger(logger Logger) Option {
+	return func(cfg *clientConfig) error {
+		if logger == nil {
+			return &InvalidOptionError{Option: "WithLogger", Message: "logger is nil"}
+		}
+		cfg.logger = logger
+		return nil
+	}
+}
+
+// WithMetrics makes the Client call fn after each call to Client.Do,
+// e.g. to export request counts and latencies.
+// fn is called from the goroutine that called Client.Do.
+func WithMetrics(fn func(RequestMetrics)) Option {
+	return func(cfg *clientConfig) error {
+		if fn == nil {
+			return &InvalidOptionError{Option: "WithMetrics", Message: "metrics function is nil"}
+		}
+		cfg.metrics = fn
+		return nil
+	}
+}
+
+// WithCache makes the Client store successful GET responses in cache
+// and serve them from there until they expire.
+// policy decides the TTL of each response; if nil, DefaultCacheTTLs().Policy() is used.
+func WithCache(cache Cache, policy CachePolicy) Option {
+	return func(cfg *clientConfig) error {
+		if cache == nil {
+			return &InvalidOptionError{Option: "WithCache", Message: "cache is nil"}
+		}
+		if policy == nil {
+			policy = DefaultCacheTTLs().Policy()
+		}
+		cfg.cache = cache
+		cfg.cachePolicy = policy
+		return nil
+	}
+}
+
+// WithRetryPolicy makes the Client retry failed requests according to policy.
+// See DefaultRetryPolicy for a sensible starting point.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(cfg *clientConfig) error {
+		if err := policy.validate(); err != nil {
+			return &InvalidOptionError{Option: "WithRetryPolicy", Message: err.Error()}
+		}
+		cfg.retryPolicy = policy
+		return nil
+	}
+}
+
+// WithRateLimit limits the Client to requestsPerSecond requests per second,
+// allowing bursts of up to burst requests. See NewTokenBucket.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(cfg *clientConfig) error {
+		limiter, err := NewTokenBucket(requestsPerSecond, burst)
+		if err != nil {
+			return &InvalidOptionError{Option: "WithRateLimit", Message: err.Error()}
+		}
+		cfg.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithRateLimiter makes the Client wait on limiter before sending each request.
+// It can be used to share a RateLimiter between several Clients.
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(cfg *clientConfig) error {
+		if limiter == nil {
+			return &InvalidOptionError{Option: "WithRateLimiter", Message: "limiter is nil"}
+		}
+		cfg.rateLimiter = limiter
+		return nil
+	}
+}
+
+// WithMaxConcurrency limits the number of requests the Client
+// has in flight at the same time across all services.
+func WithMaxConcurrency(n int) Option {
+	return func(cfg *clientConfig) error {
+		if n < 1 {
+			return &InvalidOptionError{Option: "WithMaxConcurrency", Message: "n must be at least 1"}
+		}
+		cfg.maxInFlight = n
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package gohn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// DefaultThreadPageSize is the number of top-level comments loaded
+// by ThreadPager.NextPage when ThreadPagerOptions.PageSize is not set.
+const DefaultThreadPageSize = 30
+
+// ThreadPagerOptions configures ItemsService.NewThreadPager.
+type ThreadPagerOptions struct {
+	// PageSize is the number of top-level comments loaded by each call to NextPage.
+	// If zero, DefaultThreadPageSize is used.
+	PageSize int
+	// Concurrency is the number of comments fetched concurrently.
+	// If zero, DefaultFetchWorkers is used.
+	Concurrency int
+	// Processor, if not nil, is applied to each retrieved comment.
+	// Comments for which it returns an error are not loaded, nor can their replies be.
+	Processor ItemProcessor
+}
+
+// ThreadPager loads the comments of a story a few at a time, so that the
+// beginning of a long discussion can be shown before the whole thread is retrieved.
+// Top-level comments are loaded in pages with NextPage, the replies
+// to a comment only when they are asked for with Expand.
+// A ThreadPager is not safe for concurrent use.
+type ThreadPager struct {
+	// Story holds the story and the comments loaded so far, whose Position
+	// is kept up to date (see Story.SortComments with SortHNOrder).
+	Story *Story
+
+	items    *ItemsService
+	pageSize int
+	getOpts  *GetManyOptions
+	// loaded maps the ID of the story and of each loaded comment
+	// to the number of its kids that have been requested.
+	loaded map[int]int
+	// failed maps the ID of the story and of the loaded comments to their
+	// kids that could not be retrieved, in order, to request them again.
+	failed map[int][]int
+}
+
+// NewThreadPager returns a ThreadPager for story. No comment is loaded until NextPage is called.
+// A nil opts is equivalent to the zero ThreadPagerOptions.
+func (s *ItemsService) NewThreadPager(story *Item, opts *ThreadPagerOptions) (*ThreadPager, error) {
+	if story == nil || story.ID == nil {
+		return nil, InvalidItemError{Message: "story is nil or has no ID"}
+	}
+	if opts == nil {
+		opts = &ThreadPagerOptions{}
+	}
+	if opts.PageSize < 0 {
+		return nil, errors.New("page size must not be negative")
+	}
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		pageSize = DefaultThreadPageSize
+	}
+	return &ThreadPager{
+		Story:    &Story{Parent: story, CommentsByIdMap: ItemsIndex{}},
+		items:    s,
+		pageSize: pageSize,
+		getOpts:  &GetManyOptions{Concurrency: opts.Concurrency, Processor: opts.Processor},
+		loaded:   map[int]int{*story.ID: 0},
+		failed:   map[int][]int{},
+	}, nil
+}
+
+// NextPage loads the next page of top-level comments and returns them in order.
+// It returns no comments once all of them are loaded (see HasMore).
+// The comments that cannot be retrieved are skipped and reported
+// in the returned error, of type ItemErrors, together with the loaded ones.
+// They are requested again, before the following ones, by the next call,
+// unless the API returned null for them (ErrItemNotFound).
+func (p *ThreadPager) NextPage(ctx context.Context) ([]*Item, error) {
+	return p.load(ctx, p.Story.Parent, p.pageSize)
+}
+
+// Expand loads up to limit more replies to the loaded comment with the given ID
+// and returns them in order. If limit is zero or negative, all the remaining replies are loaded.
+// Errors are reported as in NextPage.
+func (p *ThreadPager) Expand(ctx context.Context, id int, limit int) ([]*Item, error) {
+	parent := p.Story.CommentsByIdMap[id]
+	if id == *p.Story.Parent.ID {
+		parent = p.Story.Parent
+	}
+	if parent == nil {
+		return nil, InvalidItemError{Message: fmt.Sprintf("comment %d is not loaded", id)}
+	}
+	if limit <= 0 {
+		limit = p.MoreReplies(id)
+	}
+	return p.load(ctx, parent, limit)
+}
+
+// MoreReplies returns the number of replies to the loaded comment with the given ID
+// that have not been requested yet or could not be retrieved, e.g. to show "N more replies".
+// For the story, it counts the top-level comments in the same way.
+// It returns 0 for comments that are not loaded.
+func (p *ThreadPager) MoreReplies(id int) int {
+	requested, ok := p.loaded[id]
+	if !ok {
+		return 0
+	}
+	parent := p.Story.CommentsByIdMap[id]
+	if id == *p.Story.Parent.ID {
+		parent = p.Story.Parent
+	}
+	return len(parent.GetKids()) - requested + len(p.failed[id])
+}
+
+// HasMore reports whether there are top-level comments left for NextPage.
+func (p *ThreadPager) HasMore() bool {
+	return p.MoreReplies(*p.Story.Parent.ID) > 0
+}
+
+// load retrieves up to limit of the kids of parent that could not be retrieved
+// before or that have not been requested yet, in this order.
+func (p *ThreadPager) load(ctx context.Context, parent *Item, limit int) ([]*Item, error) {
+	kids := parent.GetKids()
+	failed := p.failed[*parent.ID]
+	if limit < len(failed) {
+		failed = failed[:limit]
+	}
+	start := p.loaded[*parent.ID]
+	end := start + limit - len(failed)
+	if end > len(kids) {
+		end = len(kids)
+	}
+	if end < start {
+		end = start
+	}
+	ids := append(append([]int{}, failed...), kids[start:end]...)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	items, err := p.items.GetMany(ctx, ids, p.getOpts)
+	var itemErrs ItemErrors
+	if err != nil && !errors.As(err, &itemErrs) {
+		return nil, err
+	}
+	retry := append([]int{}, p.failed[*parent.ID][len(failed):]...)
+	for _, e := range itemErrs {
+		e.Parent = *parent.ID
+		if !errors.Is(e.Err, ErrItemNotFound) {
+			retry = append(retry, e.ID)
+		}
+	}
+	p.failed[*parent.ID] = sortByKids(retry, kids)
+	p.loaded[*parent.ID] = end
+
+	var comments []*Item
+	for _, item := range items {
+		if item == nil || item.ID == nil {
+			continue
+		}
+		if _, ok := p.loaded[*item.ID]; ok {
+			continue
+		}
+		p.loaded[*item.ID] = 0
+		p.Story.CommentsByIdMap[*item.ID] = item
+		comments = append(comments, item)
+	}
+	if err := p.Story.SortComments(SortHNOrder); err != nil {
+		return comments, err
+	}
+
+	if len(itemErrs) > 0 {
+		return comments, itemErrs
+	}
+	return comments, nil
+}
+
+// sortByKids orders ids as they are in kids.
+func sortByKids(ids []int, kids []int) []int {
+	if len(ids) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(kids))
+	for i, kid := range kids {
+		index[kid] = i
+	}
+	sort.Slice(ids, func(i, j int) bool { return index[ids[i]] < index[ids[j]] })
+	return ids
+}
@@ -0,0 +1,91 @@
+package gohn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Poll is a poll together with its options, as returned by ItemsService.GetPoll.
+type Poll struct {
+	Poll *PollItem
+	// Options holds the options of the poll, in the order of PollItem.Parts.
+	Options []*PollOption
+	// TotalVotes is the sum of the scores of Options.
+	TotalVotes int
+	// Failed lists, in order, the options that could not be retrieved or that
+	// do not belong to the poll (see ErrPollMismatch). They are not in Options.
+	Failed []*ItemError
+}
+
+// PollOption is an option of a Poll.
+type PollOption struct {
+	*PollOptItem
+	// Percentage is the share of the votes of the poll that went
+	// to the option, between 0 and 100.
+	Percentage float64
+}
+
+// ErrPollMismatch is reported by ItemsService.GetPoll for the parts of a poll
+// that are not pollopts or whose poll is another item.
+var ErrPollMismatch = errors.New("item is not an option of the poll")
+
+// GetPoll returns the poll with the given ID and its options.
+// The options are retrieved concurrently with GetMany.
+// Options that cannot be retrieved or do not link back to the poll
+// are reported in Poll.Failed and left out of the tally.
+// It returns an InvalidItemError if the item is not a poll.
+func (s *ItemsService) GetPoll(ctx context.Context, id int) (*Poll, error) {
+	item, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if item == nil {
+		return nil, &ItemError{ID: id, Err: ErrItemNotFound}
+	}
+	pollItem, err := NewPollItem(item)
+	if err != nil {
+		return nil, err
+	}
+
+	poll := &Poll{Poll: pollItem}
+	if len(pollItem.Parts) == 0 {
+		return poll, nil
+	}
+	opts, err := s.GetMany(ctx, pollItem.Parts, nil)
+	var itemErrs ItemErrors
+	if err != nil && !errors.As(err, &itemErrs) {
+		return nil, err
+	}
+	failed := make(map[int]*ItemError, len(itemErrs))
+	for _, e := range itemErrs {
+		e.Parent = id
+		failed[e.ID] = e
+	}
+
+	for i, partID := range pollItem.Parts {
+		if opts[i] == nil {
+			if e, ok := failed[partID]; ok {
+				poll.Failed = append(poll.Failed, e)
+				delete(failed, partID)
+			}
+			continue
+		}
+		opt, err := NewPollOptItem(opts[i])
+		if err == nil && opt.Poll != id {
+			err = fmt.Errorf("pollopt belongs to poll %d", opt.Poll)
+		}
+		if err != nil {
+			poll.Failed = append(poll.Failed, &ItemError{ID: partID, Parent: id, Err: fmt.Errorf("%w: %v", ErrPollMismatch, err)})
+			continue
+		}
+		poll.Options = append(poll.Options, &PollOption{PollOptItem: opt})
+		poll.TotalVotes += opt.Score
+	}
+	if poll.TotalVotes > 0 {
+		for _, opt := range poll.Options {
+			opt.Percentage = 100 * float64(opt.Score) / float64(poll.TotalVotes)
+		}
+	}
+	return poll, nil
+}
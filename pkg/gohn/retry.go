@@ -0,0 +1,186 @@
+package gohn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures how Client.Do retries failed requests.
+// A request is retried when the transport returns a retryable error
+// or when the API replies with one of the RetryableStatusCodes.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values lower than 2 disable retries.
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the delay between two attempts.
+	MaxBackoff time.Duration
+	// Multiplier is applied to the delay after each retry (exponential backoff).
+	// Values lower than 1 are treated as 1.
+	Multiplier float64
+	// Jitter randomizes each delay by up to the given fraction of it
+	// (e.g. 0.2 means ±20%). It must be between 0 and 1.
+	Jitter float64
+	// RetryableStatusCodes lists the HTTP status codes that should be retried.
+	RetryableStatusCodes []int
+	// IsRetryableError reports whether a transport error should be retried.
+	// If nil, IsTemporaryError is used.
+	IsRetryableError func(error) bool
+	// RespectRetryAfter makes the Client wait for the duration in the
+	// Retry-After header of the response, when present, instead of the backoff.
+	// The wait is still capped by MaxBackoff.
+	RespectRetryAfter bool
+	// OnRetry, if not nil, is called before waiting for each retry.
+	OnRetry func(RetryAttempt)
+}
+
+// RetryAttempt describes a failed attempt that is about to be retried.
+type RetryAttempt struct {
+	// Request is the request that failed.
+	Request *http.Request
+	// Attempt is the number of the attempt that failed, starting from 1.
+	Attempt int
+	// Response is the response of the failed attempt, if any.
+	Response *http.Response
+	// Err is the error of the failed attempt.
+	Err error
+	// Wait is the delay before the next attempt.
+	Wait time.Duration
+}
+
+// DefaultRetryPolicy returns a RetryPolicy with 4 attempts, exponential backoff
+// starting at 200ms, 20% jitter and retries on 429 and 5xx errors.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RespectRetryAfter: true,
+	}
+}
+
+// IsTemporaryError reports whether err is a transport error that is likely
+// to go away on retry: timeouts, connection resets and refusals and
+// connections closed before the response was complete.
+// Context cancellation is never considered temporary.
+func IsTemporaryError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED)
+}
+
+// validate checks that the values of the policy make sense.
+func (p RetryPolicy) validate() error {
+	switch {
+	case p.InitialBackoff < 0 || p.MaxBackoff < 0:
+		return errors.New("backoff durations must not be negative")
+	case p.Jitter < 0 || p.Jitter > 1:
+		return errors.New("jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// shouldRetry reports whether an attempt that failed with err can be retried.
+func (p RetryPolicy) shouldRetry(err error) bool {
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		for _, code := range p.RetryableStatusCodes {
+			if respErr.Response.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	}
+	if p.IsRetryableError != nil {
+		return p.IsRetryableError(err)
+	}
+	return IsTemporaryError(err)
+}
+
+// backoff returns the delay before the retry following the given attempt (starting from 1).
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if p.RespectRetryAfter && resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+				wait = p.MaxBackoff
+			}
+			return wait
+		}
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	wait := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && wait > float64(p.MaxBackoff) {
+		wait = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		wait += wait * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(wait)
+}
+
+// parseRetryAfter parses the value of a Retry-After header,
+// which is either a number of seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sleep waits for d or until ctx is done, whichever comes first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,98 @@
+package gohn
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortStrategy orders the replies to the same comment (or the top-level comments)
+// in a CommentTree: it reports whether a must come before b.
+// Comments that a SortStrategy does not order are sorted by ID,
+// so that the result is always the same.
+type SortStrategy func(a, b *CommentNode) bool
+
+// SortHNOrder keeps the comments in the order of the Kids of their parent,
+// i.e. as they appear on the website.
+func SortHNOrder(a, b *CommentNode) bool {
+	return a.index < b.index
+}
+
+// SortChronological puts the oldest comments first.
+func SortChronological(a, b *CommentNode) bool {
+	return a.Item.GetTime().Before(b.Item.GetTime())
+}
+
+// SortReverseChronological puts the newest comments first.
+func SortReverseChronological(a, b *CommentNode) bool {
+	return a.Item.GetTime().After(b.Item.GetTime())
+}
+
+// SortBySubtreeSize puts the comments with the most replies, direct or not, first.
+func SortBySubtreeSize(a, b *CommentNode) bool {
+	return a.Size() > b.Size()
+}
+
+// SortByAuthor sorts the comments by the username of their author, ignoring case,
+// and the comments of the same author chronologically.
+func SortByAuthor(a, b *CommentNode) bool {
+	byA, byB := strings.ToLower(a.Item.GetBy()), strings.ToLower(b.Item.GetBy())
+	if byA != byB {
+		return byA < byB
+	}
+	return SortChronological(a, b)
+}
+
+// Sort orders the replies to each comment, the top-level comments
+// and the replies to orphans according to strategy.
+// The nesting of the comments is preserved. Orphans stay sorted by ID.
+func (t *CommentTree) Sort(strategy SortStrategy) {
+	var sortChildren func(nodes []*CommentNode)
+	sortChildren = func(nodes []*CommentNode) {
+		sortNodes(nodes, strategy)
+		for _, node := range nodes {
+			sortChildren(node.Children)
+		}
+	}
+	sortChildren(t.Roots)
+	for _, orphan := range t.Orphans {
+		sortChildren(orphan.Children)
+	}
+}
+
+// SortComments orders the comments of the story according to strategy
+// (see CommentTree.Sort) and sets their Position accordingly, so that
+// GetOrderedCommentsIDs returns them in the new order.
+// Orphans (see CommentTree) come after the other comments.
+// The Positions are unique and follow the pre-order of the tree, so that
+// with SortHNOrder the comments are in the same order as on the website.
+func (s *Story) SortComments(strategy SortStrategy) error {
+	tree, err := NewCommentTree(s)
+	if err != nil {
+		return err
+	}
+	tree.Sort(strategy)
+
+	var position int
+	setPosition := func(node *CommentNode) bool {
+		order := position
+		node.Item.Position = &order
+		position++
+		return true
+	}
+	tree.PreOrder(setPosition)
+	preOrder(tree.Orphans, setPosition)
+	return nil
+}
+
+func sortNodes(nodes []*CommentNode, strategy SortStrategy) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if strategy(a, b) {
+			return true
+		}
+		if strategy(b, a) {
+			return false
+		}
+		return a.Item.GetID() < b.Item.GetID()
+	})
+}
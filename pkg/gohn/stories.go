@@ -2,6 +2,7 @@ package gohn
 
 import (
 	"context"
+	"errors"
 	"sort"
 )
 
@@ -139,3 +140,130 @@ func (s *StoriesService) GetIDsFromURL(ctx context.Context, url string) ([]*int,
 	}
 	return ids, nil
 }
+
+// ListOptions configures the methods returning hydrated story lists,
+// such as StoriesService.GetTopStories.
+type ListOptions struct {
+	// Offset is the number of stories at the top of the list to skip.
+	Offset int
+	// Limit is the maximum number of stories to return.
+	// If zero, all the stories of the list are returned.
+	Limit int
+	// Concurrency is the number of stories fetched concurrently.
+	// If zero, DefaultFetchWorkers is used.
+	Concurrency int
+	// Processor, if not nil, is applied to each story.
+	// The stories for which it returns an error are skipped and replaced
+	// by the ones following them in the list, up to Limit.
+	Processor ItemProcessor
+}
+
+// GetTopStories returns the top stories on Hacker News, in ranking order.
+func (s *StoriesService) GetTopStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, TOP_STORIES_URL, opts)
+}
+
+// GetBestStories returns the best stories on Hacker News, in ranking order.
+func (s *StoriesService) GetBestStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, BEST_STORIES_URL, opts)
+}
+
+// GetNewStories returns the newest stories on Hacker News, in ranking order.
+func (s *StoriesService) GetNewStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, NEW_STORIES_URL, opts)
+}
+
+// GetAskStories returns the latest Ask stories on Hacker News, in ranking order.
+func (s *StoriesService) GetAskStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, ASK_STORIES_URL, opts)
+}
+
+// GetShowStories returns the latest Show stories on Hacker News, in ranking order.
+func (s *StoriesService) GetShowStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, SHOW_STORIES_URL, opts)
+}
+
+// GetJobStories returns the latest Job stories on Hacker News, in ranking order.
+func (s *StoriesService) GetJobStories(ctx context.Context, opts *ListOptions) ([]*Item, error) {
+	return s.GetStoriesFromURL(ctx, JOB_STORIES_URL, opts)
+}
+
+// GetStoriesFromURL retrieves the IDs of a stories list endpoint and
+// returns the corresponding stories, in the same order.
+// The stories are fetched concurrently, in batches of the size still missing
+// to reach opts.Limit, but of at least opts.Concurrency stories so that all the
+// workers are used when the Processor skips many of them. The stories that cannot
+// be retrieved before reaching opts.Limit are skipped and reported in the returned
+// error, of type ItemErrors, together with the stories that were retrieved.
+// A nil opts is equivalent to the zero ListOptions.
+func (s *StoriesService) GetStoriesFromURL(ctx context.Context, url string, opts *ListOptions) ([]*Item, error) {
+	if opts == nil {
+		opts = &ListOptions{}
+	}
+	if opts.Offset < 0 || opts.Limit < 0 {
+		return nil, errors.New("offset and limit must not be negative")
+	}
+	idPtrs, err := s.GetIDsFromURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, id := range idPtrs {
+		if id != nil {
+			ids = append(ids, *id)
+		}
+	}
+	if opts.Offset >= len(ids) {
+		return []*Item{}, nil
+	}
+	remaining := ids[opts.Offset:]
+
+	stories := []*Item{}
+	var errs ItemErrors
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultFetchWorkers
+	}
+	getManyOpts := &GetManyOptions{Concurrency: concurrency, Processor: opts.Processor}
+	for len(remaining) > 0 && (opts.Limit == 0 || len(stories) < opts.Limit) {
+		n := len(remaining)
+		if missing := opts.Limit - len(stories); opts.Limit > 0 && missing < n {
+			if missing < concurrency {
+				missing = concurrency
+			}
+			if missing < n {
+				n = missing
+			}
+		}
+		batch := remaining[:n]
+		remaining = remaining[n:]
+
+		items, err := s.client.Items.GetMany(ctx, batch, getManyOpts)
+		batchErrs := map[int]*ItemError{}
+		if err != nil {
+			var itemErrs ItemErrors
+			if !errors.As(err, &itemErrs) {
+				return nil, err
+			}
+			for _, e := range itemErrs {
+				batchErrs[e.ID] = e
+			}
+		}
+		// the batch may go past opts.Limit: the stories after it are dropped
+		for i, item := range items {
+			if opts.Limit > 0 && len(stories) >= opts.Limit {
+				break
+			}
+			if item != nil {
+				stories = append(stories, item)
+			} else if e, ok := batchErrs[batch[i]]; ok {
+				errs = append(errs, e)
+				delete(batchErrs, batch[i])
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return stories, errs
+	}
+	return stories, nil
+}
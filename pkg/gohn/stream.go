@@ -0,0 +1,458 @@
+package gohn
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StreamEventType is the type of an event sent by the Firebase streaming API.
+// https://firebase.google.com/docs/reference/rest/database#section-streaming
+type StreamEventType string
+
+// Types of StreamEvent.
+const (
+	// StreamPut replaces the value at Path with Data.
+	StreamPut StreamEventType = "put"
+	// StreamPatch merges the children in Data into the value at Path.
+	StreamPatch StreamEventType = "patch"
+	// StreamKeepAlive is sent periodically and carries no data.
+	StreamKeepAlive StreamEventType = "keep-alive"
+	// StreamCancel is sent when the server stops the stream.
+	StreamCancel StreamEventType = "cancel"
+	// StreamAuthRevoked is sent when the credentials of the stream expired.
+	StreamAuthRevoked StreamEventType = "auth_revoked"
+)
+
+// StreamEvent is an event received from a stream opened with Client.Subscribe.
+type StreamEvent struct {
+	Type StreamEventType
+	// Path is the location of the change, relative to the subscribed path.
+	// It is "/" when the whole value changed.
+	Path string
+	// Data is the JSON value of a put event, the JSON object of the children
+	// of a patch event or the message of a cancel event.
+	Data json.RawMessage
+}
+
+// Decode stores the Data of the event in the value pointed to by v.
+func (e StreamEvent) Decode(v any) error {
+	return json.Unmarshal(e.Data, v)
+}
+
+// StreamOptions configures the streams opened with Client.Subscribe
+// and the Subscribe methods of the services.
+type StreamOptions struct {
+	// ReconnectDelay is the delay before reconnecting after the stream ends.
+	// It doubles after each consecutive failure, up to MaxReconnectDelay.
+	// If zero, 1 second is used.
+	ReconnectDelay time.Duration
+	// MaxReconnectDelay caps the delay between two reconnections.
+	// If zero, 30 seconds is used.
+	MaxReconnectDelay time.Duration
+	// MaxReconnects, if positive, is the number of consecutive failed connections
+	// after which the stream is closed.
+	MaxReconnects int
+	// OnError, if not nil, is called with each connection error.
+	OnError func(error)
+}
+
+func (o *StreamOptions) reconnectDelay(failures int) time.Duration {
+	delay := o.ReconnectDelay
+	if delay <= 0 {
+		delay = time.Second
+	}
+	maxDelay := o.MaxReconnectDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+	for i := 1; i < failures && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// Subscribe opens a Server-Sent Events stream on path (e.g. "item/8863.json"),
+// which is relative to the BaseURL of the Client, and sends the received events
+// on the returned channel. Keep-alive events are not sent.
+// The Client reconnects automatically when the connection drops;
+// the first event after each connection is a put with the whole value.
+// The channel is closed when ctx is done, after a cancel or auth_revoked event
+// or when opts.MaxReconnects consecutive connections failed.
+// A nil opts is equivalent to the zero StreamOptions.
+func (c *Client) Subscribe(ctx context.Context, path string, opts *StreamOptions) (<-chan StreamEvent, error) {
+	if opts == nil {
+		opts = &StreamOptions{}
+	}
+	// build the request once to report an invalid path right away
+	if _, err := c.NewRequest("GET", path); err != nil {
+		return nil, err
+	}
+	// streams are long lived: the timeout of the Client does not apply
+	streamClient := *c.httpClient
+	streamClient.Timeout = 0
+
+	events := make(chan StreamEvent)
+	go func() {
+		defer close(events)
+		failures := 0
+		for {
+			closed, err := c.stream(ctx, &streamClient, path, events)
+			if closed || ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				failures++
+				if opts.OnError != nil {
+					opts.OnError(err)
+				}
+				if opts.MaxReconnects > 0 && failures >= opts.MaxReconnects {
+					return
+				}
+			} else {
+				failures = 0
+			}
+			if sleep(ctx, opts.reconnectDelay(failures)) != nil {
+				return
+			}
+		}
+	}()
+	return events, nil
+}
+
+// stream connects to path once and forwards its events until the connection ends.
+// It reports whether the stream was closed by the server or by ctx,
+// in which case it must not be reopened.
+func (c *Client) stream(ctx context.Context, httpClient *http.Client, path string, events chan<- StreamEvent) (bool, error) {
+	req, err := c.NewRequest("GET", path)
+	if err != nil {
+		return true, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.Wait(ctx); err != nil {
+			return true, err
+		}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if err := CheckResponse(resp); err != nil {
+		return false, err
+	}
+
+	closed := false
+	err = readStreamEvents(resp.Body, func(event StreamEvent) bool {
+		if event.Type == StreamKeepAlive {
+			return true
+		}
+		select {
+		case events <- event:
+		case <-ctx.Done():
+			closed = true
+			return false
+		}
+		if event.Type == StreamCancel || event.Type == StreamAuthRevoked {
+			closed = true
+			return false
+		}
+		return true
+	})
+	if closed {
+		return true, nil
+	}
+	if err == io.EOF {
+		return false, nil
+	}
+	return false, err
+}
+
+// readStreamEvents parses the Server-Sent Events in r and calls fn for each of them
+// until fn returns false or r ends. It returns io.EOF when r ends.
+func readStreamEvents(r io.Reader, fn func(StreamEvent) bool) error {
+	br := bufio.NewReader(r)
+	var eventType string
+	var data []string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			// a blank line dispatches the event
+			if eventType == "" && data == nil {
+				continue
+			}
+			event, err := parseStreamEvent(eventType, strings.Join(data, "\n"))
+			eventType, data = "", nil
+			if err != nil {
+				return err
+			}
+			if !fn(event) {
+				return nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // comment
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+}
+
+func parseStreamEvent(eventType, data string) (StreamEvent, error) {
+	event := StreamEvent{Type: StreamEventType(eventType)}
+	switch event.Type {
+	case StreamPut, StreamPatch:
+		var payload struct {
+			Path string          `json:"path"`
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(data), &payload); err != nil {
+			return event, fmt.Errorf("invalid %s event: %w", eventType, err)
+		}
+		event.Path = payload.Path
+		event.Data = payload.Data
+	default:
+		event.Data = json.RawMessage(data)
+	}
+	return event, nil
+}
+
+// streamState keeps the value of a subscribed path up to date
+// by applying the put and patch events of its stream.
+type streamState struct {
+	value any
+}
+
+// apply updates the state with event and reports whether the value changed.
+func (s *streamState) apply(event StreamEvent) (bool, error) {
+	keys := splitStreamPath(event.Path)
+	switch event.Type {
+	case StreamPut:
+		var data any
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return false, err
+		}
+		s.value = setStreamPath(s.value, keys, data)
+	case StreamPatch:
+		var children map[string]any
+		if err := json.Unmarshal(event.Data, &children); err != nil {
+			return false, err
+		}
+		for key, data := range children {
+			s.value = setStreamPath(s.value, append(keys[:len(keys):len(keys)], splitStreamPath(key)...), data)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// decode stores the current value in the value pointed to by v.
+func (s *streamState) decode(v any) error {
+	raw, err := json.Marshal(s.value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, v)
+}
+
+func splitStreamPath(path string) []string {
+	var keys []string
+	for _, key := range strings.Split(path, "/") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// setStreamPath sets value at the location identified by keys inside node
+// and returns the updated node. A nil value removes the location.
+func setStreamPath(node any, keys []string, value any) any {
+	if len(keys) == 0 {
+		return value
+	}
+	switch n := node.(type) {
+	case map[string]any:
+		child := setStreamPath(n[keys[0]], keys[1:], value)
+		if child == nil {
+			delete(n, keys[0])
+		} else {
+			n[keys[0]] = child
+		}
+		return n
+	case []any:
+		if i, err := strconv.Atoi(keys[0]); err == nil && i >= 0 {
+			for len(n) <= i {
+				n = append(n, nil)
+			}
+			n[i] = setStreamPath(n[i], keys[1:], value)
+			return n
+		}
+		m := make(map[string]any, len(n))
+		for i, v := range n {
+			m[strconv.Itoa(i)] = v
+		}
+		return setStreamPath(m, keys, value)
+	default:
+		return setStreamPath(map[string]any{}, keys, value)
+	}
+}
+
+// subscribeValue subscribes to path and calls emit with the up to date value
+// of the path after each put or patch event. emit returns false to stop.
+// done is called once the subscription ends.
+func (c *Client) subscribeValue(ctx context.Context, path string, opts *StreamOptions, emit func(*streamState) bool, done func()) error {
+	events, err := c.Subscribe(ctx, path, opts)
+	if err != nil {
+		return err
+	}
+	go func() {
+		defer done()
+		var state streamState
+		for event := range events {
+			changed, err := state.apply(event)
+			if err != nil {
+				reportStreamError(opts, err)
+				continue
+			}
+			if changed && !emit(&state) {
+				break
+			}
+		}
+		// drain the events so that the stream goroutine can return
+		for range events {
+		}
+	}()
+	return nil
+}
+
+// Subscribe streams the Item with the given ID: the item is sent on the
+// returned channel when the stream opens and each time it changes.
+// The channel is closed when ctx is done. See Client.Subscribe.
+func (s *ItemsService) Subscribe(ctx context.Context, id int, opts *StreamOptions) (<-chan *Item, error) {
+	items := make(chan *Item)
+	err := s.client.subscribeValue(ctx, fmt.Sprintf(ITEM_URL, id), opts, func(state *streamState) bool {
+		var item *Item
+		if err := state.decode(&item); err != nil {
+			return reportStreamError(opts, err)
+		}
+		select {
+		case items <- item:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(items) })
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// SubscribeMaxID streams the ID of the most recent item.
+// The channel is closed when ctx is done. See Client.Subscribe.
+func (s *ItemsService) SubscribeMaxID(ctx context.Context, opts *StreamOptions) (<-chan int, error) {
+	ids := make(chan int)
+	err := s.client.subscribeValue(ctx, MAX_ITEM_ID_URL, opts, func(state *streamState) bool {
+		var id int
+		if err := state.decode(&id); err != nil {
+			return reportStreamError(opts, err)
+		}
+		select {
+		case ids <- id:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(ids) })
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Subscribe streams the IDs of a stories list endpoint (e.g. TOP_STORIES_URL).
+// The whole list is sent each time it changes.
+// The channel is closed when ctx is done. See Client.Subscribe.
+func (s *StoriesService) Subscribe(ctx context.Context, url string, opts *StreamOptions) (<-chan []int, error) {
+	lists := make(chan []int)
+	err := s.client.subscribeValue(ctx, url, opts, func(state *streamState) bool {
+		var idPtrs []*int
+		if err := state.decode(&idPtrs); err != nil {
+			return reportStreamError(opts, err)
+		}
+		ids := make([]int, 0, len(idPtrs))
+		for _, id := range idPtrs {
+			if id != nil {
+				ids = append(ids, *id)
+			}
+		}
+		select {
+		case lists <- ids:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(lists) })
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// Subscribe streams the changed items and profiles.
+// The Update is sent each time it changes.
+// The channel is closed when ctx is done. See Client.Subscribe.
+func (s *UpdatesService) Subscribe(ctx context.Context, opts *StreamOptions) (<-chan *Update, error) {
+	updates := make(chan *Update)
+	err := s.client.subscribeValue(ctx, UPDATES_URL, opts, func(state *streamState) bool {
+		var update *Update
+		if err := state.decode(&update); err != nil {
+			return reportStreamError(opts, err)
+		}
+		select {
+		case updates <- update:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}, func() { close(updates) })
+	if err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// reportStreamError passes err to opts.OnError, if any. It always returns true
+// so that the subscription goes on with the next event.
+func reportStreamError(opts *StreamOptions, err error) bool {
+	if opts != nil && opts.OnError != nil {
+		opts.OnError(err)
+	}
+	return true
+}
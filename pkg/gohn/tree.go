@@ -0,0 +1,194 @@
+package gohn
+
+import "sort"
+
+// CommentNode is a comment in a CommentTree.
+type CommentNode struct {
+	Item *Item
+	// Parent is the node of the parent comment,
+	// or nil for top-level comments and orphans.
+	Parent *CommentNode
+	// Children holds the replies to the comment, in order.
+	Children []*CommentNode
+	// Depth is 1 for top-level comments, 2 for their replies, and so on.
+	// For orphans and their replies it is relative to the orphan, whose depth is 1.
+	Depth int
+
+	// index is the position of the comment among the Kids of its parent.
+	index int
+}
+
+// CommentTree links the comments of a Story to their parent and replies.
+// Build it with NewCommentTree.
+type CommentTree struct {
+	Story *Item
+	// Roots holds the top-level comments, in the order of the Kids of Story
+	// unless the tree is sorted with Sort.
+	Roots []*CommentNode
+	// Orphans holds the comments that cannot be reached from Story,
+	// usually because their parent was not retrieved, sorted by ID.
+	// Their replies are linked to them as usual.
+	Orphans []*CommentNode
+
+	nodes map[int]*CommentNode
+}
+
+// NewCommentTree builds the CommentTree of story.
+// The replies of each comment are ordered as its Kids;
+// the kids that are missing from story.CommentsByIdMap are ignored.
+func NewCommentTree(story *Story) (*CommentTree, error) {
+	if story == nil || story.Parent == nil {
+		return nil, InvalidItemError{Message: "story is nil"}
+	}
+	t := &CommentTree{
+		Story: story.Parent,
+		nodes: make(map[int]*CommentNode, len(story.CommentsByIdMap)),
+	}
+
+	var link func(parent *CommentNode, kids []int, depth int) []*CommentNode
+	link = func(parent *CommentNode, kids []int, depth int) []*CommentNode {
+		var nodes []*CommentNode
+		for i, id := range kids {
+			item, ok := story.CommentsByIdMap[id]
+			if !ok || item == nil || t.nodes[id] != nil {
+				continue
+			}
+			node := &CommentNode{Item: item, Parent: parent, Depth: depth, index: i}
+			t.nodes[id] = node
+			node.Children = link(node, item.GetKids(), depth+1)
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	t.Roots = link(nil, story.Parent.GetKids(), 1)
+
+	var missing []int
+	for id, item := range story.CommentsByIdMap {
+		if item != nil && t.nodes[id] == nil {
+			missing = append(missing, id)
+		}
+	}
+	sort.Ints(missing)
+	// the orphans are the comments whose parent is not among the missing ones;
+	// the comments left after them are in a cycle and are orphans too
+	for pass := 0; pass < 2; pass++ {
+		for _, id := range missing {
+			if t.nodes[id] != nil {
+				continue
+			}
+			parentID := story.CommentsByIdMap[id].GetParent()
+			if pass == 0 && story.CommentsByIdMap[parentID] != nil && t.nodes[parentID] == nil {
+				continue
+			}
+			t.Orphans = append(t.Orphans, link(nil, []int{id}, 1)...)
+		}
+	}
+	sort.Slice(t.Orphans, func(i, j int) bool {
+		return t.Orphans[i].Item.GetID() < t.Orphans[j].Item.GetID()
+	})
+	return t, nil
+}
+
+// Find returns the node of the comment with the given ID, or nil if it is not in the tree.
+// Orphans and their replies are found too.
+func (t *CommentTree) Find(id int) *CommentNode {
+	return t.nodes[id]
+}
+
+// Len returns the number of comments in the tree, including orphans and their replies.
+func (t *CommentTree) Len() int {
+	return len(t.nodes)
+}
+
+// Size returns the number of comments reachable from the story, i.e. excluding orphans.
+func (t *CommentTree) Size() int {
+	var n int
+	for _, root := range t.Roots {
+		n += root.Size()
+	}
+	return n
+}
+
+// PreOrder calls fn for each comment reachable from the story, each comment
+// before its replies. Unless the tree is sorted with Sort, this is the order
+// of the comments on the website.
+// The traversal stops when fn returns false.
+func (t *CommentTree) PreOrder(fn func(*CommentNode) bool) {
+	preOrder(t.Roots, fn)
+}
+
+// PostOrder calls fn for each comment reachable from the story,
+// each comment after its replies. The traversal stops when fn returns false.
+func (t *CommentTree) PostOrder(fn func(*CommentNode) bool) {
+	postOrder(t.Roots, fn)
+}
+
+// BFS calls fn for each comment reachable from the story, level by level:
+// the top-level comments first, then their replies, and so on.
+// The traversal stops when fn returns false.
+func (t *CommentTree) BFS(fn func(*CommentNode) bool) {
+	bfs(t.Roots, fn)
+}
+
+// Size returns the number of comments in the subtree of n, including n.
+func (n *CommentNode) Size() int {
+	size := 1
+	for _, child := range n.Children {
+		size += child.Size()
+	}
+	return size
+}
+
+// PathToRoot returns n and its ancestors, up to its top-level comment (or orphan).
+func (n *CommentNode) PathToRoot() []*CommentNode {
+	var path []*CommentNode
+	for node := n; node != nil; node = node.Parent {
+		path = append(path, node)
+	}
+	return path
+}
+
+// PreOrder is like CommentTree.PreOrder for the subtree of n.
+func (n *CommentNode) PreOrder(fn func(*CommentNode) bool) {
+	preOrder([]*CommentNode{n}, fn)
+}
+
+// PostOrder is like CommentTree.PostOrder for the subtree of n.
+func (n *CommentNode) PostOrder(fn func(*CommentNode) bool) {
+	postOrder([]*CommentNode{n}, fn)
+}
+
+// BFS is like CommentTree.BFS for the subtree of n.
+func (n *CommentNode) BFS(fn func(*CommentNode) bool) {
+	bfs([]*CommentNode{n}, fn)
+}
+
+func preOrder(nodes []*CommentNode, fn func(*CommentNode) bool) bool {
+	for _, node := range nodes {
+		if !fn(node) || !preOrder(node.Children, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+func postOrder(nodes []*CommentNode, fn func(*CommentNode) bool) bool {
+	for _, node := range nodes {
+		if !postOrder(node.Children, fn) || !fn(node) {
+			return false
+		}
+	}
+	return true
+}
+
+func bfs(nodes []*CommentNode, fn func(*CommentNode) bool) {
+	queue := append([]*CommentNode(nil), nodes...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if !fn(node) {
+			return
+		}
+		queue = append(queue, node.Children...)
+	}
+}
@@ -2,6 +2,8 @@
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
 // UpdatesService handles communication with the updates
@@ -36,3 +38,152 @@ func (s UpdatesService) Get(ctx context.Context) (*Update, error) {
 
 	return updates, nil
 }
+
+// UpdateKind is the kind of change reported by an UpdateEvent.
+type UpdateKind int
+
+// Kinds of UpdateEvent. The zero UpdateKind is used for events reporting
+// that the updates could not be retrieved.
+const (
+	ItemChanged UpdateKind = iota + 1
+	ProfileChanged
+)
+
+// UpdateEvent is sent by UpdatesService.Watch for each changed item or profile.
+type UpdateEvent struct {
+	Kind UpdateKind
+	// ItemID is the ID of the changed item, for ItemChanged events.
+	ItemID int
+	// Username is the ID of the changed user, for ProfileChanged events.
+	Username string
+	// Item is the changed item, if WatchOptions.HydrateItems is set.
+	Item *Item
+	// User is the changed user, if WatchOptions.HydrateUsers is set.
+	User *User
+	// Err is set when the updates, the Item or the User could not be retrieved.
+	Err error
+}
+
+// WatchOptions configures UpdatesService.Watch.
+type WatchOptions struct {
+	// HydrateItems makes Watch retrieve each changed Item.
+	HydrateItems bool
+	// HydrateUsers makes Watch retrieve each changed User.
+	HydrateUsers bool
+}
+
+// Watch polls the updates endpoint every interval and sends an UpdateEvent
+// on the returned channel for each item and profile that changed.
+// IDs that were already reported by the previous poll are not sent again.
+// The updates, items and users are always retrieved from the API,
+// never from the Cache of the Client.
+// The channel is closed when ctx is done.
+// A nil opts is equivalent to the zero WatchOptions.
+func (s *UpdatesService) Watch(ctx context.Context, interval time.Duration, opts *WatchOptions) (<-chan UpdateEvent, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+	if opts == nil {
+		opts = &WatchOptions{}
+	}
+	events := make(chan UpdateEvent)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastItems map[int]bool
+		var lastProfiles map[string]bool
+		for {
+			lastItems, lastProfiles = s.poll(ctx, opts, events, lastItems, lastProfiles)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return events, nil
+}
+
+// poll retrieves the updates once and sends the events for the items and profiles
+// that are not in lastItems and lastProfiles. It returns the sets of items and profiles
+// to compare the next poll with.
+func (s *UpdatesService) poll(ctx context.Context, opts *WatchOptions, events chan<- UpdateEvent, lastItems map[int]bool, lastProfiles map[string]bool) (map[int]bool, map[string]bool) {
+	send := func(event UpdateEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// the updates, and the items and users they report as changed,
+	// must not be served from the Cache of the Client
+	fresh := skipCache(ctx)
+	update, err := s.Get(fresh)
+	if err != nil || update == nil {
+		if err != nil && ctx.Err() == nil {
+			send(UpdateEvent{Err: err})
+		}
+		return lastItems, lastProfiles
+	}
+
+	currentItems := map[int]bool{}
+	var changedItems []int
+	if update.Items != nil {
+		for _, id := range *update.Items {
+			if !currentItems[id] && !lastItems[id] {
+				changedItems = append(changedItems, id)
+			}
+			currentItems[id] = true
+		}
+	}
+	currentProfiles := map[string]bool{}
+	var changedProfiles []string
+	if update.Profiles != nil {
+		for _, username := range *update.Profiles {
+			if !currentProfiles[username] && !lastProfiles[username] {
+				changedProfiles = append(changedProfiles, username)
+			}
+			currentProfiles[username] = true
+		}
+	}
+
+	var items []*Item
+	var itemErrs map[int]error
+	if opts.HydrateItems && len(changedItems) > 0 {
+		items, err = s.client.Items.GetMany(fresh, changedItems, nil)
+		var errs ItemErrors
+		if errors.As(err, &errs) {
+			itemErrs = make(map[int]error, len(errs))
+			for _, e := range errs {
+				itemErrs[e.ID] = e
+			}
+		} else if err != nil {
+			return lastItems, lastProfiles
+		}
+	}
+	for i, id := range changedItems {
+		event := UpdateEvent{Kind: ItemChanged, ItemID: id}
+		if items != nil {
+			event.Item = items[i]
+			event.Err = itemErrs[id]
+		}
+		if !send(event) {
+			return currentItems, currentProfiles
+		}
+	}
+
+	for _, username := range changedProfiles {
+		event := UpdateEvent{Kind: ProfileChanged, Username: username}
+		if opts.HydrateUsers {
+			event.User, event.Err = s.client.Users.GetByUsername(fresh, username)
+		}
+		if !send(event) {
+			return currentItems, currentProfiles
+		}
+	}
+	return currentItems, currentProfiles
+}
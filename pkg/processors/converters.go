@@ -0,0 +1,254 @@
+package processors
+
+import (
+	"html"
+	"strings"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// allowedTags are the tags Hacker News uses in the text of items.
+var allowedTags = map[string]bool{"p": true, "a": true, "i": true, "pre": true, "code": true}
+
+// ToText converts the HTML text of an item to plain text.
+// Paragraphs are separated by an empty line, links become "label (url)"
+// (or just the URL when the label is the URL), code blocks are kept
+// as they are and entities are unescaped.
+func ToText(s string) string {
+	return convert(s, false)
+}
+
+// ToMarkdown converts the HTML text of an item to Markdown.
+// Paragraphs are separated by an empty line, links become [label](url)
+// (or <url> when the label is the URL), italics become *text*, code blocks
+// become fenced code blocks and the lines quoted with "> " become blockquotes.
+// The rest of the text is escaped, so that it is never rendered as HTML
+// or as other Markdown syntax (e.g. headings and lists), and only http and
+// https links are kept: the other ones are replaced by their label.
+func ToMarkdown(s string) string {
+	return convert(s, true)
+}
+
+// Sanitize keeps only the tags Hacker News allows in the text of items
+// (<p>, <a>, <i>, <pre> and <code>) and escapes everything else.
+// Links keep only their href, if it is an http or https URL, and get rel="nofollow".
+// Unclosed tags are closed at the end.
+func Sanitize(s string) string {
+	var b strings.Builder
+	var open []string
+	for _, tok := range tokenize(s) {
+		switch {
+		case tok.kind == textToken:
+			b.WriteString(html.EscapeString(tok.text))
+		case !allowedTags[tok.tag]:
+			// the tag is dropped, its content is kept
+		case tok.tag == "p":
+			if tok.kind == startTagToken {
+				b.WriteString("<p>")
+			}
+		case tok.kind == startTagToken:
+			if tok.tag == "a" {
+				if href := tok.attrs["href"]; isWebURL(href) {
+					b.WriteString(`<a href="` + html.EscapeString(href) + `" rel="nofollow">`)
+				} else {
+					b.WriteString("<a>")
+				}
+			} else {
+				b.WriteString("<" + tok.tag + ">")
+			}
+			open = append(open, tok.tag)
+		default:
+			// close the tag only if it is open, with the ones opened after it
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == tok.tag {
+					for j := len(open) - 1; j >= i; j-- {
+						b.WriteString("</" + open[j] + ">")
+					}
+					open = open[:i]
+					break
+				}
+			}
+		}
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// HTMLToText converts the text of the item to plain text. See ToText.
+func HTMLToText() gohn.ItemProcessor {
+	return textModifier(ToText)
+}
+
+// HTMLToMarkdown converts the text of the item to Markdown. See ToMarkdown.
+func HTMLToMarkdown() gohn.ItemProcessor {
+	return textModifier(ToMarkdown)
+}
+
+// SanitizeHTML removes the tags that Hacker News does not allow
+// from the text of the item. See Sanitize.
+func SanitizeHTML() gohn.ItemProcessor {
+	return textModifier(Sanitize)
+}
+
+// textModifier returns a processor that replaces the text of the item with fn(text).
+func textModifier(fn func(string) string) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil || item.Text == nil {
+			return false, nil
+		}
+		*item.Text = fn(*item.Text)
+		return false, nil
+	}
+}
+
+// convert converts s to plain text or, if markdown is true, to Markdown.
+func convert(s string, markdown bool) string {
+	var paragraphs []string
+	var b strings.Builder
+	endParagraph := func() {
+		if p := strings.TrimSpace(b.String()); p != "" {
+			if markdown {
+				p = escapeLineStarts(p)
+			}
+			paragraphs = append(paragraphs, p)
+		}
+		b.Reset()
+	}
+	endCode := func() {
+		// unlike paragraphs, code blocks keep their indentation
+		code := strings.Trim(b.String(), "\n")
+		if markdown {
+			code += "\n```"
+		}
+		paragraphs = append(paragraphs, code)
+		b.Reset()
+	}
+
+	var link *token
+	var label strings.Builder
+	var inPre bool
+	for _, tok := range tokenize(s) {
+		switch {
+		case tok.kind == textToken:
+			switch {
+			case inPre:
+				b.WriteString(tok.text)
+			case link != nil:
+				label.WriteString(tok.text)
+			case markdown:
+				b.WriteString(escapeMarkdown(tok.text))
+			default:
+				b.WriteString(tok.text)
+			}
+		case tok.tag == "p" && tok.kind == startTagToken && !inPre:
+			endParagraph()
+		case tok.tag == "pre" && tok.kind == startTagToken && !inPre:
+			endParagraph()
+			inPre = true
+			if markdown {
+				b.WriteString("```\n")
+			}
+		case tok.tag == "pre" && inPre:
+			endCode()
+			inPre = false
+		case tok.tag == "i" && markdown && !inPre:
+			if link != nil {
+				continue
+			}
+			b.WriteString("*")
+		case tok.tag == "a" && tok.kind == startTagToken && !inPre:
+			tok := tok
+			link = &tok
+			label.Reset()
+		case tok.tag == "a" && link != nil:
+			b.WriteString(formatLink(link.attrs["href"], label.String(), markdown))
+			link = nil
+		}
+	}
+	if link != nil {
+		b.WriteString(formatLink(link.attrs["href"], label.String(), markdown))
+	}
+	if inPre {
+		endCode()
+	}
+	endParagraph()
+	return strings.Join(paragraphs, "\n\n")
+}
+
+// formatLink renders a link with the given href and label.
+func formatLink(href, label string, markdown bool) string {
+	label = strings.TrimSpace(label)
+	switch {
+	case href == "":
+		if markdown {
+			return escapeMarkdown(label)
+		}
+		return label
+	case markdown && !isWebURL(href):
+		return escapeMarkdown(label)
+	case label == "" || label == href || isTruncated(label, href):
+		if markdown {
+			return "<" + markdownURLEscaper.Replace(href) + ">"
+		}
+		return href
+	case markdown:
+		return "[" + escapeMarkdown(label) + "](" + markdownURLEscaper.Replace(href) + ")"
+	}
+	return label + " (" + href + ")"
+}
+
+// isTruncated reports whether label is href shortened by Hacker News,
+// which cuts long URLs and appends "...".
+func isTruncated(label, href string) bool {
+	prefix := strings.TrimSuffix(label, "...")
+	return prefix != label && strings.HasPrefix(href, prefix)
+}
+
+func isWebURL(s string) bool {
+	lower := strings.ToLower(s)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// markdownEscaper escapes the characters with a meaning in Markdown.
+// The HTML special characters become entities, so that no HTML is rendered.
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`,
+	"&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// markdownURLEscaper escapes the characters that would end a link in Markdown.
+var markdownURLEscaper = strings.NewReplacer("(", "%28", ")", "%29", "<", "%3C", ">", "%3E", " ", "%20")
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// EscapeMarkdown escapes plain text, such as the title of a story,
+// so that it is rendered as it is in Markdown, inline (see ToMarkdown).
+func EscapeMarkdown(s string) string {
+	return escapeMarkdown(s)
+}
+
+// escapeLineStarts escapes the characters that start a heading ("#"),
+// a list ("-", "+", "1.") or a blockquote at the beginning of the lines
+// of an escaped paragraph. The lines starting with "> " are the quotes of
+// Hacker News, which are kept as blockquotes.
+func escapeLineStarts(p string) string {
+	lines := strings.Split(p, "\n")
+	for i, line := range lines {
+		text := strings.TrimLeft(line, " ")
+		indent := line[:len(line)-len(text)]
+		switch {
+		case strings.HasPrefix(text, "&gt;"):
+			lines[i] = indent + ">" + strings.TrimPrefix(text, "&gt;")
+		case strings.HasPrefix(text, "#"), strings.HasPrefix(text, "-"), strings.HasPrefix(text, "+"):
+			lines[i] = indent + `\` + text
+		default:
+			digits := len(text) - len(strings.TrimLeft(text, "0123456789"))
+			if digits > 0 && digits < len(text) && (text[digits] == '.' || text[digits] == ')') {
+				lines[i] = indent + text[:digits] + `\` + text[digits:]
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,9 +1,9 @@
 ﻿/*
 Package processors contains functions to process items.
 
-The processor takes a pointer to the item and to a waitgroup.
-It is the responsibility of the processor to Add to the waitgroup and to call Done() when it is done processing the item.
-Failing to do so will cause the program to behave in unpredictable ways.
+The processor takes a pointer to the item.
+It may be called concurrently from several goroutines,
+so it must not modify shared state without synchronization.
 
 The processor returns a boolean and an error.
 The boolean is used to signal to the caller if it should exclude the kids of the item from the processing when an error is returned.
@@ -12,9 +12,7 @@ Example:
 
 	// This processor will return an error if the item has no text
 	// and it will exclude the kids of the item from the processing
-	processor := func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
-		wg.Add(1)
-		defer wg.Done()
+	processor := func(item *gohn.Item) (bool, error) {
 		if item.Text == nil {
 			return true, fmt.Errorf("item has no text")
 		}
@@ -27,9 +25,7 @@ Example:
 
 	// This processor will return an error if the item has no text
 	// and it will include the kids of the item in the processing
-	processor := func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
-		wg.Add(1)
-		defer wg.Done()
+	processor := func(item *gohn.Item) (bool, error) {
 		if item.Text == nil {
 			return false, fmt.Errorf("item has no text")
 		}
@@ -39,9 +35,7 @@ Example:
 If you need to further process the items, you can send the item to a channel.
 Example:
 
-	processor := func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
-		wg.Add(1)
-		defer wg.Done()
+	processor := func(item *gohn.Item) (bool, error) {
 		if item.Text == nil {
 			return false, fmt.Errorf("item has no text")
 		}
@@ -49,6 +43,21 @@ Example:
 		return false, nil
 	}
 
+Processors can also be written as Rules, which return an explicit Decision,
+and combined with And, Or, Not, Sequence and If or in a Pipeline.
+FromProcessor turns the existing processors into Rules.
+
+Example:
+
+	// Drop the comments of spammer with their replies, and the deleted ones
+	pipeline := processors.NewPipeline(func(item *gohn.Item) processors.Decision {
+		if item.GetBy() == "spammer" {
+			return processors.DropSubtree
+		}
+		return processors.Keep
+	}).ThenProcessor(processors.FilterOutDeleted())
+	comments, err := hn.Items.FetchAllDescendants(ctx, story, pipeline.Processor())
+
 */
 
 package processors
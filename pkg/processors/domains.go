@@ -0,0 +1,193 @@
+package processors
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// publicSuffixes are the suffixes with more than one label under which
+// domains can be registered, including the hosting services giving each user
+// a subdomain. This is a small subset of the Public Suffix List, covering
+// the domains commonly found on Hacker News.
+var publicSuffixes = map[string]bool{
+	"co.uk": true, "org.uk": true, "ac.uk": true, "gov.uk": true,
+	"com.au": true, "net.au": true, "org.au": true, "edu.au": true,
+	"co.jp": true, "ne.jp": true, "or.jp": true, "ac.jp": true,
+	"co.nz": true, "com.br": true, "co.in": true, "com.cn": true, "com.mx": true,
+	"github.io": true, "gitlab.io": true, "blogspot.com": true, "wordpress.com": true,
+	"substack.com": true, "herokuapp.com": true, "netlify.app": true, "vercel.app": true,
+	"pages.dev": true,
+}
+
+// ownerSites are the sites hosting the content of many users, where the first
+// segment of the path identifies the owner: like Hacker News, Site reports
+// "github.com/user" rather than "github.com" for them.
+var ownerSites = map[string]bool{
+	"github.com": true, "gitlab.com": true, "bitbucket.org": true, "codeberg.org": true,
+	"medium.com": true, "twitter.com": true,
+}
+
+// domainAliases maps the registrable domains that are mirrors or
+// short links of another one to it.
+var domainAliases = map[string]string{
+	"x.com":    "twitter.com",
+	"youtu.be": "youtube.com",
+	"redd.it":  "reddit.com",
+}
+
+// CanonicalURL returns a form of rawURL in which the duplicates of the
+// same article have the same URL: on top of the changes of NormalizeURL,
+// the scheme becomes https, "www." is removed from the host and
+// the trailing slashes are removed from the path.
+func CanonicalURL(rawURL string) (string, error) {
+	link, err := NormalizeURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = "https"
+	u.Host = strings.TrimPrefix(u.Host, "www.")
+	u.Path = strings.TrimRight(u.Path, "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// RegistrableDomain returns the part of host that can be registered,
+// e.g. "example.co.uk" for "blog.example.co.uk" and "user.github.io"
+// for "user.github.io". See publicSuffixes for the suffixes it knows.
+// IP addresses are returned unchanged.
+func RegistrableDomain(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	n := 2
+	if publicSuffixes[strings.Join(labels[len(labels)-2:], ".")] {
+		n = 3
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// Site returns the site of rawURL, as shown by Hacker News next to the titles:
+// its registrable domain (see RegistrableDomain), with the mirrors replaced
+// by the original site (e.g. "youtube.com" for "youtu.be") and followed by
+// the owner for the sites hosting many users (e.g. "github.com/golang").
+func Site(rawURL string) (string, error) {
+	link, err := NormalizeURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", err
+	}
+	site := RegistrableDomain(u.Hostname())
+	if alias, ok := domainAliases[site]; ok {
+		site = alias
+	}
+	if ownerSites[site] {
+		if owner := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)[0]; owner != "" {
+			site += "/" + strings.ToLower(owner)
+		}
+	}
+	return site, nil
+}
+
+// onDomains reports whether rawURL is on one of the domains or their subdomains,
+// ignoring "www." and the mirrors (see Site). A domain can be followed by an owner,
+// e.g. "github.com/golang", to match only the URLs of that owner.
+func onDomains(rawURL string, domains []string) bool {
+	link, err := NormalizeURL(rawURL)
+	if err != nil {
+		return false
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := unalias(strings.TrimPrefix(u.Hostname(), "www."))
+	owner := strings.SplitN(strings.TrimPrefix(u.Path, "/"), "/", 2)[0]
+	for _, d := range domains {
+		d = strings.ToLower(strings.TrimSpace(d))
+		d = strings.TrimPrefix(strings.TrimPrefix(d, "https://"), "http://")
+		d = strings.TrimRight(strings.TrimPrefix(d, "www."), "/")
+		domain, wantOwner := d, ""
+		if i := strings.Index(d, "/"); i >= 0 {
+			domain, wantOwner = d[:i], d[i+1:]
+		}
+		domain = unalias(domain)
+		subdomain := net.ParseIP(host) == nil && strings.HasSuffix(host, "."+domain)
+		if domain == "" || host != domain && !subdomain {
+			continue
+		}
+		if wantOwner == "" || strings.EqualFold(owner, wantOwner) {
+			return true
+		}
+	}
+	return false
+}
+
+// unalias replaces the registrable domain of host with the original site if it is a mirror.
+func unalias(host string) string {
+	domain := RegistrableDomain(host)
+	if alias, ok := domainAliases[domain]; ok {
+		return strings.TrimSuffix(host, domain) + alias
+	}
+	return host
+}
+
+// FilterOutDomains filters items whose URL is on one of the given domains
+// or their subdomains. "www." and the mirrors are ignored, so that "twitter.com"
+// also filters the links to "x.com", and a domain can be followed by an owner
+// to filter only the URLs of that owner, e.g. "github.com/someone".
+// Items without URL are not filtered.
+func FilterOutDomains(domains []string) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.URL != nil && onDomains(*item.URL, domains) {
+			return false, fmt.Errorf("item %d is on a filtered domain", item.GetID())
+		}
+		return false, nil
+	}
+}
+
+// FilterOutDuplicateURLs filters items whose URL was already seen by the
+// processor in another item, comparing the URLs with CanonicalURL.
+// Items without URL are not filtered. The processor is safe for concurrent use.
+func FilterOutDuplicateURLs() gohn.ItemProcessor {
+	var mu sync.Mutex
+	seen := map[string]int{}
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.URL == nil {
+			return false, nil
+		}
+		link, err := CanonicalURL(*item.URL)
+		if err != nil {
+			return false, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if id, ok := seen[link]; ok && id != item.GetID() {
+			return false, fmt.Errorf("item %d is a duplicate of item %d", item.GetID(), id)
+		}
+		seen[link] = item.GetID()
+		return false, nil
+	}
+}
@@ -3,7 +3,6 @@
 import (
 	"fmt"
 	"strings"
-	"sync"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
 )
@@ -11,13 +10,13 @@ import (
 // FilterOutWords filters items that contain the given words in the title or text.
 // The argument title is a boolean that indicates if the filter
 // should be applied to the title and not the text.
+// The words also match inside longer words: see FilterOutMatching and Words
+// to match whole words only.
 func FilterOutWords(words []string, title bool) gohn.ItemProcessor {
-	return func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
+	return func(item *gohn.Item) (bool, error) {
 		if item == nil {
 			return true, nil
 		}
-		wg.Add(1)
-		defer wg.Done()
 		for _, word := range words {
 			if title && item.Title != nil {
 				if strings.Contains(strings.ToLower(*item.Title), strings.ToLower(word)) {
@@ -38,12 +37,10 @@ func FilterOutWords(words []string, title bool) gohn.ItemProcessor {
 
 // FilterOutDeleted filters deleted items
 func FilterOutDeleted() gohn.ItemProcessor {
-	return func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
+	return func(item *gohn.Item) (bool, error) {
 		if item == nil {
 			return true, nil
 		}
-		wg.Add(1)
-		defer wg.Done()
 		if item.Deleted != nil && *item.Deleted {
 			return false, fmt.Errorf("Deleted item found")
 		}
@@ -53,15 +50,13 @@ func FilterOutDeleted() gohn.ItemProcessor {
 
 // FilterOutUsers filters items that are not from the given user
 func FilterOutUsers(users []string) gohn.ItemProcessor {
-	return func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
+	return func(item *gohn.Item) (bool, error) {
 		if item == nil {
 			return true, nil
 		}
 		if item.By == nil {
 			return false, nil
 		}
-		wg.Add(1)
-		defer wg.Done()
 		for _, user := range users {
 			if *item.By == user {
 				return false, fmt.Errorf("User found")
@@ -0,0 +1,140 @@
+package processors
+
+import (
+	"html"
+	"strings"
+)
+
+// tokenKind is the kind of a token of HN's HTML.
+type tokenKind int
+
+const (
+	textToken tokenKind = iota
+	startTagToken
+	endTagToken
+)
+
+// token is a piece of HTML: some text or a tag.
+type token struct {
+	kind tokenKind
+	// text is the unescaped text of a textToken.
+	text string
+	// tag is the lowercase name of a tag.
+	tag string
+	// attrs holds the unescaped attributes of a start tag, by lowercase name.
+	attrs map[string]string
+}
+
+// tokenize splits s into text and tags. It only understands the subset of
+// HTML used by Hacker News: comments are dropped and a "<" that does not
+// start a tag is treated as text.
+func tokenize(s string) []token {
+	var tokens []token
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			tokens = append(tokens, token{kind: textToken, text: html.UnescapeString(text.String())})
+			text.Reset()
+		}
+	}
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			text.WriteString(s)
+			break
+		}
+		text.WriteString(s[:i])
+		s = s[i:]
+
+		if strings.HasPrefix(s, "<!--") {
+			end := strings.Index(s, "-->")
+			if end < 0 {
+				break
+			}
+			s = s[end+len("-->"):]
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		var tok token
+		ok := end > 0
+		if ok {
+			tok, ok = parseTag(s[1:end])
+		}
+		if !ok {
+			text.WriteByte('<')
+			s = s[1:]
+			continue
+		}
+		flush()
+		tokens = append(tokens, tok)
+		s = s[end+1:]
+	}
+	flush()
+	return tokens
+}
+
+// parseTag parses the content of a tag, between "<" and ">".
+func parseTag(s string) (token, bool) {
+	tok := token{kind: startTagToken}
+	if strings.HasPrefix(s, "/") {
+		tok.kind = endTagToken
+		s = s[1:]
+	}
+	n := 0
+	for n < len(s) && isTagNameChar(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return token{}, false
+	}
+	tok.tag = strings.ToLower(s[:n])
+	if tok.kind == startTagToken {
+		tok.attrs = parseAttrs(s[n:])
+	}
+	return tok, true
+}
+
+// parseAttrs parses attributes like name="value", name='value', name=value or name.
+func parseAttrs(s string) map[string]string {
+	attrs := map[string]string{}
+	for {
+		s = strings.TrimLeft(s, " \t\r\n/")
+		if s == "" {
+			return attrs
+		}
+		n := strings.IndexAny(s, "= \t\r\n/")
+		if n < 0 {
+			n = len(s)
+		}
+		name := strings.ToLower(s[:n])
+		s = strings.TrimLeft(s[n:], " \t\r\n")
+		var value string
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], " \t\r\n")
+			if s != "" && (s[0] == '"' || s[0] == '\'') {
+				end := strings.IndexByte(s[1:], s[0]) + 1
+				if end == 0 {
+					end = len(s)
+				}
+				value = s[1:end]
+				if end < len(s) {
+					end++ // skip the closing quote
+				}
+				s = s[end:]
+			} else {
+				end := strings.IndexAny(s, " \t\r\n")
+				if end < 0 {
+					end = len(s)
+				}
+				value, s = s[:end], s[end:]
+			}
+		}
+		if name != "" {
+			attrs[name] = html.UnescapeString(value)
+		}
+	}
+}
+
+func isTagNameChar(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
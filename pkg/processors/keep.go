@@ -0,0 +1,104 @@
+package processors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// The KeepOnly processors are the opposite of the FilterOut ones:
+// they filter the items that do not satisfy a condition.
+// They never exclude the kids of the filtered items, so that, for example,
+// the replies by a user to a comment by someone else are still retrieved.
+
+// KeepOnlyUsers filters items that are not from one of the given users.
+func KeepOnlyUsers(users []string) gohn.ItemProcessor {
+	allowed := make(map[string]bool, len(users))
+	for _, user := range users {
+		allowed[user] = true
+	}
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if !allowed[item.GetBy()] {
+			return false, fmt.Errorf("item %d is not from one of the users", item.GetID())
+		}
+		return false, nil
+	}
+}
+
+// KeepOnlyMatching filters items in which none of the matchers matches
+// any of the given fields. See FilterOutMatching.
+func KeepOnlyMatching(fields Field, matchers ...Matcher) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if findMatch(item, fields, matchers) == nil {
+			return false, fmt.Errorf("item %d does not match", item.GetID())
+		}
+		return false, nil
+	}
+}
+
+// KeepOnlyDomains filters items whose URL is not on one of the given domains
+// or their subdomains. The domains are matched like in FilterOutDomains.
+// Items without URL, such as comments, are filtered too.
+func KeepOnlyDomains(domains []string) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.URL == nil || !onDomains(*item.URL, domains) {
+			return false, fmt.Errorf("item %d is not on one of the domains", item.GetID())
+		}
+		return false, nil
+	}
+}
+
+// KeepOnlyTypes filters items that are not of one of the given types.
+func KeepOnlyTypes(types ...gohn.ItemType) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		for _, t := range types {
+			if item.Is(t) {
+				return false, nil
+			}
+		}
+		return false, fmt.Errorf("item %d is a %q", item.GetID(), item.Kind())
+	}
+}
+
+// KeepOnlyBetween filters items created before from or after to.
+// A zero from or to means no bound. Items without time are filtered.
+func KeepOnlyBetween(from, to time.Time) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		t := item.GetTime()
+		if item.Time == nil || !from.IsZero() && t.Before(from) || !to.IsZero() && t.After(to) {
+			return false, fmt.Errorf("item %d was not created in the time window", item.GetID())
+		}
+		return false, nil
+	}
+}
+
+// KeepOnlyScoreBetween filters items whose score is lower than min or higher than max.
+// A max of zero means no upper bound. Items without score, such as comments, are filtered.
+func KeepOnlyScoreBetween(min, max int) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		score := item.GetScore()
+		if item.Score == nil || score < min || max != 0 && score > max {
+			return false, fmt.Errorf("item %d has a score of %d", item.GetID(), score)
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,229 @@
+package processors
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// HNURL is the URL relative links in the text of items are resolved against.
+const HNURL = "https://news.ycombinator.com/"
+
+// trackingParams are the query parameters removed by NormalizeURL,
+// in addition to those starting with "utm_".
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true,
+	"mc_cid": true, "mc_eid": true, "igshid": true, "_hsenc": true, "_hsmi": true,
+	"mkt_tok": true, "ref_src": true,
+}
+
+var hnBaseURL, _ = url.Parse(HNURL)
+
+// NormalizeURL returns a canonical form of rawURL, so that the different
+// ways of writing the same link can be compared:
+// relative URLs (e.g. "item?id=1") are resolved against HNURL,
+// the scheme and host are lowercased, default ports and fragments are removed,
+// tracking parameters (utm_*, fbclid, gclid...) are removed and the other ones sorted.
+// Only http and https URLs are accepted. See CanonicalURL to detect duplicate articles.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := hnBaseURL.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported URL scheme %q", u.Scheme)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("URL %q has no host", rawURL)
+	}
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for name := range query {
+		if trackingParams[strings.ToLower(name)] || strings.HasPrefix(strings.ToLower(name), "utm_") {
+			query.Del(name)
+		}
+	}
+	u.RawQuery = query.Encode()
+	u.ForceQuery = false
+	return u.String(), nil
+}
+
+// Domain returns the host of a URL returned by NormalizeURL, without "www.".
+func Domain(normalizedURL string) string {
+	u, err := url.Parse(normalizedURL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(u.Hostname(), "www.")
+}
+
+// ExtractLinks returns the normalized URLs of the links in the text of the item
+// and its URL, if any (stories and jobs have one), in order and without duplicates.
+// The links that cannot be normalized are ignored.
+func ExtractLinks(item *gohn.Item) []string {
+	if item == nil {
+		return nil
+	}
+	var links []string
+	seen := map[string]bool{}
+	add := func(rawURL string) {
+		link, err := NormalizeURL(rawURL)
+		if err == nil && !seen[link] {
+			seen[link] = true
+			links = append(links, link)
+		}
+	}
+	if item.URL != nil {
+		add(*item.URL)
+	}
+	for _, tok := range tokenize(item.GetText()) {
+		if tok.kind == startTagToken && tok.tag == "a" && tok.attrs["href"] != "" {
+			add(tok.attrs["href"])
+		}
+	}
+	return links
+}
+
+// LinkMentions lists the items that link to a URL or to a domain.
+type LinkMentions struct {
+	// Key is the URL or the domain.
+	Key string
+	// ItemIDs holds the IDs of the items with the link, sorted.
+	ItemIDs []int
+}
+
+// LinkIndex collects the links found in the items of a story
+// (see ExtractLinks), with the items that mention them.
+// It is safe for concurrent use, so that its Processor can be
+// passed to ItemsService.FetchAllDescendants.
+type LinkIndex struct {
+	mu    sync.Mutex
+	byURL map[string]map[int]bool
+}
+
+// NewLinkIndex returns an empty LinkIndex.
+func NewLinkIndex() *LinkIndex {
+	return &LinkIndex{byURL: map[string]map[int]bool{}}
+}
+
+// IndexStory returns a LinkIndex with the links of the story and of its comments.
+func IndexStory(story *gohn.Story) *LinkIndex {
+	x := NewLinkIndex()
+	if story == nil {
+		return x
+	}
+	x.Add(story.Parent)
+	for _, comment := range story.CommentsByIdMap {
+		x.Add(comment)
+	}
+	return x
+}
+
+// Add adds the links of the item to the index.
+func (x *LinkIndex) Add(item *gohn.Item) {
+	links := ExtractLinks(item)
+	if len(links) == 0 {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for _, link := range links {
+		if x.byURL[link] == nil {
+			x.byURL[link] = map[int]bool{}
+		}
+		x.byURL[link][item.GetID()] = true
+	}
+}
+
+// Processor returns a processor that adds each item to the index.
+// It never filters items out.
+func (x *LinkIndex) Processor() gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		x.Add(item)
+		return false, nil
+	}
+}
+
+// Mentions returns the IDs of the items that link to rawURL, sorted.
+func (x *LinkIndex) Mentions(rawURL string) []int {
+	link, err := NormalizeURL(rawURL)
+	if err != nil {
+		return nil
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return sortedIDs(x.byURL[link])
+}
+
+// URLs returns the URLs in the index, the most mentioned first
+// (and in alphabetical order for the same number of mentions).
+func (x *LinkIndex) URLs() []LinkMentions {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	var urls []LinkMentions
+	for link, ids := range x.byURL {
+		urls = append(urls, LinkMentions{Key: link, ItemIDs: sortedIDs(ids)})
+	}
+	sortMentions(urls)
+	return urls
+}
+
+// Domains returns the domains of the URLs in the index (see Domain),
+// the most mentioned first (and in alphabetical order for the same number of mentions).
+func (x *LinkIndex) Domains() []LinkMentions {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	byDomain := map[string]map[int]bool{}
+	for link, ids := range x.byURL {
+		domain := Domain(link)
+		if byDomain[domain] == nil {
+			byDomain[domain] = map[int]bool{}
+		}
+		for id := range ids {
+			byDomain[domain][id] = true
+		}
+	}
+	var domains []LinkMentions
+	for domain, ids := range byDomain {
+		domains = append(domains, LinkMentions{Key: domain, ItemIDs: sortedIDs(ids)})
+	}
+	sortMentions(domains)
+	return domains
+}
+
+func sortedIDs(set map[int]bool) []int {
+	if len(set) == 0 {
+		return nil
+	}
+	ids := make([]int, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func sortMentions(mentions []LinkMentions) {
+	sort.Slice(mentions, func(i, j int) bool {
+		if len(mentions[i].ItemIDs) != len(mentions[j].ItemIDs) {
+			return len(mentions[i].ItemIDs) > len(mentions[j].ItemIDs)
+		}
+		return mentions[i].Key < mentions[j].Key
+	})
+}
@@ -0,0 +1,216 @@
+package processors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// Field is a set of fields of an item that a Matcher is applied to.
+// Fields can be combined, e.g. FieldTitle|FieldText.
+type Field int
+
+const (
+	// FieldTitle is the title of stories, jobs and polls.
+	FieldTitle Field = 1 << iota
+	// FieldText is the text of the item, converted to plain text with ToText.
+	FieldText
+	// FieldURL is the URL of stories and jobs.
+	FieldURL
+	// FieldAuthor is the username of the author of the item.
+	FieldAuthor
+
+	// AllFields is the set of all the fields.
+	AllFields = FieldTitle | FieldText | FieldURL | FieldAuthor
+)
+
+var fieldNames = []struct {
+	field Field
+	name  string
+}{
+	{FieldTitle, "title"},
+	{FieldText, "text"},
+	{FieldURL, "url"},
+	{FieldAuthor, "author"},
+}
+
+func (f Field) String() string {
+	var names []string
+	for _, fn := range fieldNames {
+		if f&fn.field != 0 {
+			names = append(names, fn.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// value returns the value of the single field f of item, and whether it is set.
+func (f Field) value(item *gohn.Item) (string, bool) {
+	switch f {
+	case FieldTitle:
+		return item.GetTitle(), item.Title != nil
+	case FieldText:
+		return ToText(item.GetText()), item.Text != nil
+	case FieldURL:
+		return item.GetURL(), item.URL != nil
+	case FieldAuthor:
+		return item.GetBy(), item.By != nil
+	}
+	return "", false
+}
+
+// Matcher finds a pattern in the fields of items.
+type Matcher interface {
+	// Find returns the first match of the pattern in s, if any.
+	Find(s string) (string, bool)
+	// String describes the pattern. It is reported in MatchError.
+	String() string
+}
+
+// regexpMatcher is a Matcher backed by a regular expression.
+// The match is the capturing group with the given index (0 for the whole match).
+// A nil re never matches.
+type regexpMatcher struct {
+	re    *regexp.Regexp
+	group int
+	desc  string
+}
+
+func (m *regexpMatcher) Find(s string) (string, bool) {
+	if m.re == nil {
+		return "", false
+	}
+	loc := m.re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return "", false
+	}
+	return s[loc[2*m.group]:loc[2*m.group+1]], true
+}
+
+func (m *regexpMatcher) String() string {
+	return m.desc
+}
+
+// wordBoundaries matches whole words only: the match must not be
+// preceded or followed by a letter, a digit or an underscore.
+const wordBoundaries = `(?i)(?:^|[^\pL\pN_])(%s)(?:$|[^\pL\pN_])`
+
+// Words matches any of the given words, ignoring case. Only whole words match:
+// "go" matches "Go is fun" and "I like go!" but not "good".
+func Words(words ...string) Matcher {
+	return wordsMatcher("words", words)
+}
+
+// Phrases matches any of the given phrases, ignoring case, as whole words.
+// The words of a phrase can be separated by any amount of whitespace
+// in the matched text: "machine learning" matches "Machine\nlearning".
+func Phrases(phrases ...string) Matcher {
+	return wordsMatcher("phrases", phrases)
+}
+
+// wordsMatcher returns a Matcher for Words and Phrases.
+func wordsMatcher(kind string, phrases []string) Matcher {
+	var alternatives, quoted []string
+	for _, phrase := range phrases {
+		words := strings.Fields(phrase)
+		if len(words) == 0 {
+			continue
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		alternatives = append(alternatives, strings.Join(words, `\s+`))
+		quoted = append(quoted, fmt.Sprintf("%q", strings.Join(strings.Fields(phrase), " ")))
+	}
+	m := &regexpMatcher{group: 1, desc: kind + " " + strings.Join(quoted, ", ")}
+	if len(alternatives) > 0 {
+		m.re = regexp.MustCompile(fmt.Sprintf(wordBoundaries, strings.Join(alternatives, "|")))
+	}
+	return m
+}
+
+// Regexp matches the regular expression re. It is case sensitive
+// unless re has the i flag, e.g. (?i)rust. A nil re never matches.
+func Regexp(re *regexp.Regexp) Matcher {
+	if re == nil {
+		return &regexpMatcher{desc: "regexp <nil>"}
+	}
+	return &regexpMatcher{re: re, desc: "regexp " + re.String()}
+}
+
+// Glob matches the whole field against pattern, ignoring case.
+// In pattern, * matches any sequence of characters and ? any single character.
+// For example, "*.github.io*" matches the URLs of GitHub pages.
+func Glob(pattern string) Matcher {
+	var expr strings.Builder
+	expr.WriteString(`(?is)^`)
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			expr.WriteString(`.*`)
+		case '?':
+			expr.WriteString(`.`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString(`$`)
+	return &regexpMatcher{re: regexp.MustCompile(expr.String()), desc: fmt.Sprintf("glob %q", pattern)}
+}
+
+// MatchError is returned by FilterOutMatching for the items it excludes.
+// It reports which rule matched, e.g. to audit the filters.
+type MatchError struct {
+	// ItemID is the ID of the item.
+	ItemID int
+	// Field is the field in which the match was found.
+	Field Field
+	// Rule describes the Matcher that matched (see Matcher.String).
+	Rule string
+	// Match is the matched text.
+	Match string
+}
+
+func (e *MatchError) Error() string {
+	return fmt.Sprintf("item %d: %s matches %s (%q)", e.ItemID, e.Field, e.Rule, e.Match)
+}
+
+// findMatch returns a MatchError for the first of the fields
+// of item in which one of the matchers matches, or nil.
+func findMatch(item *gohn.Item, fields Field, matchers []Matcher) *MatchError {
+	for _, fn := range fieldNames {
+		if fields&fn.field == 0 {
+			continue
+		}
+		value, ok := fn.field.value(item)
+		if !ok {
+			continue
+		}
+		for _, m := range matchers {
+			if match, ok := m.Find(value); ok {
+				return &MatchError{ItemID: item.GetID(), Field: fn.field, Rule: m.String(), Match: match}
+			}
+		}
+	}
+	return nil
+}
+
+// FilterOutMatching filters items in which any of the matchers matches
+// any of the given fields. The returned error is a *MatchError.
+// The kids of the filtered items are not excluded.
+func FilterOutMatching(fields Field, matchers ...Matcher) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if err := findMatch(item, fields, matchers); err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+}
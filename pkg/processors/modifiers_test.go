@@ -1,7 +1,6 @@
 ﻿package processors
 
 import (
-	"sync"
 	"testing"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
@@ -9,12 +8,11 @@ import (
 
 func TestUnescapeHTML(t *testing.T) {
 	expectedText := `This is an <a href="https://www.example.com">example</a>`
-	var wg sync.WaitGroup
 	id := 1
 	i := &gohn.Item{ID: &id, Text: &expectedText}
 
 	f := UnescapeHTML()
-	_, err := f(i, &wg)
+	_, err := f(i)
 
 	if err != nil {
 		t.Fatalf("unexpected error unescaping HTML: %v", err)
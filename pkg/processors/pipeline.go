@@ -0,0 +1,201 @@
+package processors
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// Decision tells what to do with an item. The decisions are ordered by severity.
+type Decision int
+
+const (
+	// Keep keeps the item.
+	Keep Decision = iota
+	// Drop excludes the item but not its kids.
+	Drop
+	// DropSubtree excludes the item and its kids.
+	DropSubtree
+	// Stop excludes the item and its kids and, in ItemsService.FetchDescendants
+	// and FetchAllDescendants, stops fetching items (see gohn.ErrStopFetching).
+	// Elsewhere it is equivalent to DropSubtree.
+	Stop
+)
+
+func (d Decision) String() string {
+	switch d {
+	case Keep:
+		return "keep"
+	case Drop:
+		return "drop"
+	case DropSubtree:
+		return "drop subtree"
+	case Stop:
+		return "stop"
+	}
+	return fmt.Sprintf("Decision(%d)", int(d))
+}
+
+// ErrDropped is the error returned by the processors made from Rules
+// for the items they exclude.
+var ErrDropped = errors.New("item dropped")
+
+// Rule decides what to do with an item. Like processors, rules may modify
+// the item and may be called concurrently from several goroutines.
+type Rule func(item *gohn.Item) Decision
+
+// Processor returns a processor that applies r:
+// Drop and DropSubtree return ErrDropped (with the kids excluded for DropSubtree)
+// and Stop returns gohn.ErrStopFetching, which only ItemsService.FetchDescendants
+// and FetchAllDescendants honor.
+func (r Rule) Processor() gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		switch d := r(item); d {
+		case Keep:
+			return false, nil
+		case Drop:
+			return false, ErrDropped
+		case DropSubtree:
+			return true, ErrDropped
+		case Stop:
+			return true, gohn.ErrStopFetching
+		default:
+			return false, fmt.Errorf("invalid decision %v", d)
+		}
+	}
+}
+
+// FromProcessor returns a Rule that applies p, so that the existing processors
+// can be combined with rules: no error means Keep, gohn.ErrStopFetching means Stop
+// and any other error means DropSubtree or Drop, depending on whether p excludes the kids.
+func FromProcessor(p gohn.ItemProcessor) Rule {
+	return func(item *gohn.Item) Decision {
+		skipKids, err := p(item)
+		switch {
+		case err == nil:
+			return Keep
+		case errors.Is(err, gohn.ErrStopFetching):
+			return Stop
+		case skipKids:
+			return DropSubtree
+		}
+		return Drop
+	}
+}
+
+// And keeps the items that all the rules keep. The rules are applied in order
+// until one does not keep the item, and its decision is returned.
+func And(rules ...Rule) Rule {
+	return func(item *gohn.Item) Decision {
+		for _, rule := range rules {
+			if d := rule(item); d != Keep {
+				return d
+			}
+		}
+		return Keep
+	}
+}
+
+// Or keeps the items that at least one of the rules keeps. The rules are applied
+// in order until one keeps the item. If none does, the least severe decision is returned.
+// Or without rules drops every item.
+func Or(rules ...Rule) Rule {
+	return func(item *gohn.Item) Decision {
+		decision := Drop
+		for i, rule := range rules {
+			d := rule(item)
+			if d == Keep {
+				return Keep
+			}
+			if i == 0 || d < decision {
+				decision = d
+			}
+		}
+		return decision
+	}
+}
+
+// Not drops the items that rule keeps and keeps the items that rule drops.
+// Stop is not inverted.
+func Not(rule Rule) Rule {
+	return func(item *gohn.Item) Decision {
+		switch d := rule(item); d {
+		case Keep:
+			return Drop
+		case Stop:
+			return Stop
+		}
+		return Keep
+	}
+}
+
+// Sequence applies all the rules in order, even after one drops the item,
+// and returns the most severe decision. It is useful when some of the rules
+// modify or record the items. Only Stop ends the sequence early.
+func Sequence(rules ...Rule) Rule {
+	return func(item *gohn.Item) Decision {
+		decision := Keep
+		for _, rule := range rules {
+			d := rule(item)
+			if d == Stop {
+				return Stop
+			}
+			if d > decision {
+				decision = d
+			}
+		}
+		return decision
+	}
+}
+
+// If applies then to the items for which cond is true and otherwise to the other ones.
+// A nil rule keeps the items.
+func If(cond func(*gohn.Item) bool, then, otherwise Rule) Rule {
+	return func(item *gohn.Item) Decision {
+		rule := otherwise
+		if cond(item) {
+			rule = then
+		}
+		if rule == nil {
+			return Keep
+		}
+		return rule(item)
+	}
+}
+
+// Pipeline is a list of rules applied in order until one does not keep the item
+// (see And). Its Processor can be passed wherever a gohn.ItemProcessor is accepted,
+// but Stop only stops ItemsService.FetchDescendants and FetchAllDescendants.
+type Pipeline struct {
+	rules []Rule
+}
+
+// NewPipeline returns a Pipeline with the given rules.
+func NewPipeline(rules ...Rule) *Pipeline {
+	return &Pipeline{rules: rules}
+}
+
+// Then appends rules to the pipeline and returns it.
+func (p *Pipeline) Then(rules ...Rule) *Pipeline {
+	p.rules = append(p.rules, rules...)
+	return p
+}
+
+// ThenProcessor appends processors to the pipeline (see FromProcessor) and returns it.
+func (p *Pipeline) ThenProcessor(processors ...gohn.ItemProcessor) *Pipeline {
+	for _, processor := range processors {
+		p.rules = append(p.rules, FromProcessor(processor))
+	}
+	return p
+}
+
+// Decide returns the decision of the pipeline for item.
+func (p *Pipeline) Decide(item *gohn.Item) Decision {
+	return And(p.rules...)(item)
+}
+
+// Processor returns a processor that applies the pipeline. See Rule.Processor.
+func (p *Pipeline) Processor() gohn.ItemProcessor {
+	return Rule(p.Decide).Processor()
+}
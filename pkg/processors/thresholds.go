@@ -0,0 +1,140 @@
+package processors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// The threshold processors filter items on their score, number of descendants,
+// age or points per hour. The items without the field, such as comments,
+// which have no score, are not filtered, so that the processors can be used
+// with ItemsService.FetchAllDescendants. The kids of the filtered items are not excluded.
+//
+// The processors that depend on the current time take a now function returning it,
+// so that they can be tested with fixed timestamps. A nil now means time.Now.
+
+// FilterOutScoreBelow filters items with a score lower than min.
+func FilterOutScoreBelow(min int) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Score != nil && *item.Score < min {
+			return false, fmt.Errorf("item %d has a score of %d, below %d", item.GetID(), *item.Score, min)
+		}
+		return false, nil
+	}
+}
+
+// FilterOutScoreAbove filters items with a score higher than max.
+func FilterOutScoreAbove(max int) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Score != nil && *item.Score > max {
+			return false, fmt.Errorf("item %d has a score of %d, above %d", item.GetID(), *item.Score, max)
+		}
+		return false, nil
+	}
+}
+
+// FilterOutDescendantsBelow filters items with fewer than min descendants (comments).
+func FilterOutDescendantsBelow(min int) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Descendants != nil && *item.Descendants < min {
+			return false, fmt.Errorf("item %d has %d descendants, below %d", item.GetID(), *item.Descendants, min)
+		}
+		return false, nil
+	}
+}
+
+// FilterOutDescendantsAbove filters items with more than max descendants (comments).
+func FilterOutDescendantsAbove(max int) gohn.ItemProcessor {
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Descendants != nil && *item.Descendants > max {
+			return false, fmt.Errorf("item %d has %d descendants, above %d", item.GetID(), *item.Descendants, max)
+		}
+		return false, nil
+	}
+}
+
+// FilterOutOlderThan filters items created more than d before now.
+func FilterOutOlderThan(d time.Duration, now func() time.Time) gohn.ItemProcessor {
+	now = clock(now)
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Time != nil {
+			if age := now().Sub(item.GetTime()); age > d {
+				return false, fmt.Errorf("item %d is %v old, older than %v", item.GetID(), age, d)
+			}
+		}
+		return false, nil
+	}
+}
+
+// FilterOutNewerThan filters items created less than d before now,
+// e.g. to wait for the votes on the new stories.
+func FilterOutNewerThan(d time.Duration, now func() time.Time) gohn.ItemProcessor {
+	now = clock(now)
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Time != nil {
+			if age := now().Sub(item.GetTime()); age < d {
+				return false, fmt.Errorf("item %d is %v old, newer than %v", item.GetID(), age, d)
+			}
+		}
+		return false, nil
+	}
+}
+
+// PointsPerHour returns the score of the item divided by its age at now, in hours.
+// Ages shorter than a minute count as a minute.
+// It returns 0 if the item has no score or no time.
+func PointsPerHour(item *gohn.Item, now time.Time) float64 {
+	if item == nil || item.Score == nil || item.Time == nil {
+		return 0
+	}
+	age := now.Sub(item.GetTime())
+	if age < time.Minute {
+		age = time.Minute
+	}
+	return float64(*item.Score) / age.Hours()
+}
+
+// FilterOutSlowerThan filters items getting fewer than pointsPerHour points
+// per hour on average since their creation. See PointsPerHour.
+func FilterOutSlowerThan(pointsPerHour float64, now func() time.Time) gohn.ItemProcessor {
+	now = clock(now)
+	return func(item *gohn.Item) (bool, error) {
+		if item == nil {
+			return true, nil
+		}
+		if item.Score != nil && item.Time != nil {
+			if v := PointsPerHour(item, now()); v < pointsPerHour {
+				return false, fmt.Errorf("item %d gets %.2f points per hour, below %.2f", item.GetID(), v, pointsPerHour)
+			}
+		}
+		return false, nil
+	}
+}
+
+// clock returns now, or time.Now if now is nil.
+func clock(now func() time.Time) func() time.Time {
+	if now == nil {
+		return time.Now
+	}
+	return now
+}
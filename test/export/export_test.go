@@ -0,0 +1,234 @@
+package exporttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/export"
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// fixtures is a story (1) with comments 2 and 4, a reply to 2 (3),
+// a reply to 3 (5) and a deleted reply to 4 (6).
+var fixtures = []string{
+	`{"id": 1, "type": "story", "by": "alice", "time": 1700000000, "title": "Hello <World>", "url": "https://example.com", "score": 42, "descendants": 5, "kids": [2, 4]}`,
+	`{"id": 2, "type": "comment", "by": "bob", "time": 1700000060, "text": "First<p>Second &amp; last", "kids": [3], "parent": 1}`,
+	`{"id": 3, "type": "comment", "by": "carol", "time": 1700000120, "text": "Reply", "kids": [5], "parent": 2}`,
+	`{"id": 4, "type": "comment", "by": "dave", "time": 1700000180, "text": "Other", "kids": [6], "parent": 1}`,
+	`{"id": 5, "type": "comment", "by": "erin", "time": 1700000240, "text": "Deep", "parent": 3}`,
+	`{"id": 6, "type": "comment", "deleted": true, "time": 1700000300, "parent": 4}`,
+}
+
+func newStory(t *testing.T) *gohn.Story {
+	t.Helper()
+	story := &gohn.Story{CommentsByIdMap: gohn.ItemsIndex{}}
+	for _, fixture := range fixtures {
+		var item gohn.Item
+		if err := json.Unmarshal([]byte(fixture), &item); err != nil {
+			t.Fatalf("unexpected error decoding fixture: %v", err)
+		}
+		if item.Is(gohn.ItemTypeStory) {
+			story.Parent = &item
+		} else {
+			story.CommentsByIdMap[*item.ID] = &item
+		}
+	}
+	return story
+}
+
+func TestMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.Markdown(&buf, newStory(t), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# [Hello &lt;World&gt;](https://example.com)
+
+*42 points | by alice | 2023-11-14 22:13 UTC | 5 comments*
+
+---
+
+> **bob** | 2023-11-14 22:14 UTC
+>
+> First
+>
+> Second &amp; last
+>
+> > **carol** | 2023-11-14 22:15 UTC
+> >
+> > Reply
+> >
+> > > **erin** | 2023-11-14 22:17 UTC
+> > >
+> > > Deep
+> >
+>
+
+> **dave** | 2023-11-14 22:16 UTC
+>
+> Other
+
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected Markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestText(t *testing.T) {
+	var buf bytes.Buffer
+	opts := &export.Options{MaxDepth: 2, IncludeDeleted: true, Authors: true}
+	if err := export.Text(&buf, newStory(t), opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `Hello <World>
+https://example.com
+42 points | by alice | 5 comments
+
+----
+
+bob
+First
+
+Second & last
+
+  carol
+  Reply
+
+dave
+Other
+
+  [deleted]
+
+`
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected text:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.HTML(&buf, newStory(t), &export.Options{IncludeDeleted: true}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		"<title>Hello &lt;World&gt;</title>",
+		`<h1><a href="https://example.com">Hello &lt;World&gt;</a></h1>`,
+		`<li id="item-3">`,
+		`<div class="text">First<p>Second &amp; last</div>`,
+		`<p class="placeholder">[deleted]</p>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected HTML to contain %q, got:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "bob") || strings.Contains(got, "UTC") {
+		t.Errorf("expected no authors nor timestamps, got:\n%s", got)
+	}
+	if strings.Count(got, "<ul") != strings.Count(got, "</ul>") || strings.Count(got, "<li") != strings.Count(got, "</li>") {
+		t.Errorf("expected balanced lists, got:\n%s", got)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.JSON(&buf, newStory(t), &export.Options{Timestamps: true, Sort: gohn.SortReverseChronological}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	type node struct {
+		ID       int     `json:"id"`
+		By       string  `json:"by"`
+		Time     string  `json:"time"`
+		Title    string  `json:"title"`
+		Comments []*node `json:"comments"`
+	}
+	var root node
+	if err := json.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("unexpected error decoding JSON: %v\n%s", err, buf.String())
+	}
+	if root.ID != 1 || root.Title != "Hello <World>" || root.By != "" || root.Time != "2023-11-14T22:13:20Z" {
+		t.Errorf("unexpected story %+v", root)
+	}
+	// newest first, and the deleted reply to 4 is left out
+	if len(root.Comments) != 2 || root.Comments[0].ID != 4 || root.Comments[1].ID != 2 {
+		t.Fatalf("unexpected comments %+v", root.Comments)
+	}
+	if len(root.Comments[0].Comments) != 0 {
+		t.Errorf("expected no replies to 4, got %+v", root.Comments[0].Comments)
+	}
+	reply := root.Comments[1].Comments
+	if len(reply) != 1 || reply[0].ID != 3 || len(reply[0].Comments) != 1 || reply[0].Comments[0].ID != 5 {
+		t.Errorf("unexpected replies to 2 %+v", reply)
+	}
+}
+
+func TestExport_invalidStory(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.Markdown(&buf, &gohn.Story{}, nil); err == nil {
+		t.Errorf("expected error for a story without item")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing to be written, got %q", buf.String())
+	}
+}
+
+func TestHTML_sanitizesText(t *testing.T) {
+	story := newStory(t)
+	text := `Ask HN<script>alert(1)</script><p><a href="javascript:x()">click</a>`
+	story.Parent.Text = &text
+
+	var buf bytes.Buffer
+	if err := export.HTML(&buf, story, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `<div class="text">Ask HNalert(1)<p><a>click</a></div>`; !strings.Contains(buf.String(), want) {
+		t.Errorf("expected HTML to contain %q, got:\n%s", want, buf.String())
+	}
+}
+
+func TestHTML_noScriptInjection(t *testing.T) {
+	story := newStory(t)
+	url := "javascript:alert(1)"
+	story.Parent.URL = &url
+	text := `<script>alert(2)</script><img src=x onerror="alert(3)">`
+	story.CommentsByIdMap[5].Text = &text
+
+	var buf bytes.Buffer
+	if err := export.HTML(&buf, story, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, unwanted := range []string{"<script", "<img", "javascript:"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected HTML not to contain %q, got:\n%s", unwanted, out)
+		}
+	}
+	if want := "<h1>Hello &lt;World&gt;</h1>"; !strings.Contains(out, want) {
+		t.Errorf("expected HTML to contain %q, got:\n%s", want, out)
+	}
+}
+
+func TestMarkdown_noScriptInjection(t *testing.T) {
+	story := newStory(t)
+	url := "javascript:alert(1)"
+	story.Parent.URL = &url
+	title := "Hello](javascript:alert(2)) <script>alert(3)</script>"
+	story.Parent.Title = &title
+
+	var buf bytes.Buffer
+	if err := export.Markdown(&buf, story, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, unwanted := range []string{"<script", "javascript:alert(1)"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected Markdown not to contain %q, got:\n%s", unwanted, out)
+		}
+	}
+	if want := "# Hello\\](javascript:alert(2)) &lt;script&gt;alert(3)&lt;/script&gt;\n"; !strings.HasPrefix(out, want) {
+		t.Errorf("expected Markdown to start with %q, got:\n%s", want, out)
+	}
+}
@@ -0,0 +1,137 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func ancestorIDs(ancestors []gohn.Ancestor) (ids, depths []int) {
+	for _, a := range ancestors {
+		ids = append(ids, *a.Item.ID)
+		depths = append(depths, a.Depth)
+	}
+	return ids, depths
+}
+
+func TestGetAncestors(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	comment := decodeItem(t, treeFixtures[7])
+	ancestors, err := client.Items.GetAncestors(context.Background(), comment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ids, depths := ancestorIDs(ancestors)
+	if want := []int{3, 1}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected ancestors %v, got %v", want, ids)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(depths, want) {
+		t.Errorf("expected depths %v, got %v", want, depths)
+	}
+
+	root, err := client.Items.GetRoot(context.Background(), comment)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *root.ID != 1 {
+		t.Errorf("expected root 1, got %d", *root.ID)
+	}
+
+	story := decodeItem(t, treeFixtures[1])
+	if root, err := client.Items.GetRoot(context.Background(), story); err != nil || root != story {
+		t.Errorf("expected the story to be its own root, got %v, %v", root, err)
+	}
+}
+
+func TestGetAncestors_pollopt(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, pollFixtures)
+
+	ancestors, err := client.Items.GetAncestors(context.Background(), decodeItem(t, pollFixtures[126811]))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids, _ := ancestorIDs(ancestors); !reflect.DeepEqual(ids, []int{126809}) {
+		t.Errorf("expected the poll as only ancestor, got %v", ids)
+	}
+}
+
+func TestGetAncestors_errors(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "parent": 3}`,
+		3: `{"id": 3, "type": "comment", "parent": 2}`,
+		4: `{"id": 4, "type": "comment", "parent": 5}`,
+		5: `null`,
+	})
+	mux.HandleFunc("/item/7.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error", http.StatusInternalServerError)
+	})
+
+	tests := []struct {
+		name      string
+		item      string
+		ancestors []int
+		failed    int
+		err       error
+	}{
+		{"cycle", `{"id": 2, "type": "comment", "parent": 3}`, []int{3}, 2, gohn.ErrCycle},
+		{"null parent", `{"id": 6, "type": "comment", "parent": 4}`, []int{4}, 5, gohn.ErrItemNotFound},
+		{"fetch error", `{"id": 8, "type": "comment", "parent": 7}`, nil, 7, nil},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ancestors, err := client.Items.GetAncestors(context.Background(), decodeItem(t, tt.item))
+			var itemErr *gohn.ItemError
+			if !errors.As(err, &itemErr) || itemErr.ID != tt.failed {
+				t.Fatalf("expected an error for item %d, got %v", tt.failed, err)
+			}
+			if itemErr.Parent != 0 {
+				t.Errorf("expected the parent of item %d not to be set, got %d", tt.failed, itemErr.Parent)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+			if ids, _ := ancestorIDs(ancestors); !reflect.DeepEqual(ids, tt.ancestors) {
+				t.Errorf("expected partial ancestors %v, got %v", tt.ancestors, ids)
+			}
+		})
+	}
+}
+
+func TestGetAncestors_cache(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithCache(gohn.NewLRUCache(10), nil))
+	defer teardown()
+
+	var requests int32
+	for id, body := range treeFixtures {
+		body := body
+		mux.HandleFunc(fmt.Sprintf("/item/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.Write([]byte(body))
+		})
+	}
+
+	for _, id := range []int{5, 6} {
+		if _, err := client.Items.GetAncestors(context.Background(), decodeItem(t, treeFixtures[id])); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// 2 and 1 are retrieved for 5, then served from the cache for 6
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("expected 2 requests, got %d", got)
+	}
+}
@@ -0,0 +1,181 @@
+package gohntest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestLRUCache_evictsLeastRecentlyUsed(t *testing.T) {
+	cache := gohn.NewLRUCache(2)
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Minute)
+
+	// touch "a" so that "b" becomes the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatalf("expected a to be cached")
+	}
+	cache.Set("c", []byte("3"), time.Minute)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Errorf("expected b to be evicted")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("expected %s to be cached", key)
+		}
+	}
+	if cache.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestLRUCache_expiration(t *testing.T) {
+	cache := gohn.NewLRUCache(0)
+	cache.Set("expired", []byte("1"), time.Nanosecond)
+	cache.Set("forever", []byte("2"), gohn.NeverExpire)
+	time.Sleep(time.Millisecond)
+
+	if _, ok := cache.Get("expired"); ok {
+		t.Errorf("expected entry to be expired")
+	}
+	if body, ok := cache.Get("forever"); !ok || string(body) != "2" {
+		t.Errorf("expected entry to never expire, got %q", body)
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := gohn.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("unexpected error creating disk cache: %v", err)
+	}
+	cache.Set("key", []byte(`{"id": 1}`), gohn.NeverExpire)
+	cache.Set("expired", []byte(`{"id": 2}`), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+
+	// a new instance on the same directory sees the stored entries
+	reopened, err := gohn.NewDiskCache(dir)
+	if err != nil {
+		t.Fatalf("unexpected error creating disk cache: %v", err)
+	}
+	if body, ok := reopened.Get("key"); !ok || string(body) != `{"id": 1}` {
+		t.Errorf("expected cached body, got %q", body)
+	}
+	if _, ok := reopened.Get("expired"); ok {
+		t.Errorf("expected entry to be expired")
+	}
+
+	reopened.Delete("key")
+	if _, ok := cache.Get("key"); ok {
+		t.Errorf("expected entry to be deleted")
+	}
+}
+
+func TestDo_cacheHit(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithCache(gohn.NewLRUCache(10), nil))
+	defer teardown()
+
+	var requests int32
+	oldTime := time.Now().Add(-30 * 24 * time.Hour).Unix()
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		fmt.Fprintf(w, `{"id": 1, "type": "story", "time": %d}`, oldTime)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		item, err := client.Items.Get(ctx, 1)
+		if err != nil {
+			t.Fatalf("unexpected error getting item: %v", err)
+		}
+		if item == nil || *item.ID != 1 {
+			t.Fatalf("expected item 1, got %v", item)
+		}
+	}
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request to the server, got %d", n)
+	}
+	if got, want := client.CacheStats(), (gohn.CacheStats{Hits: 2, Misses: 1}); got != want {
+		t.Errorf("expected cache stats %+v, got %+v", want, got)
+	}
+}
+
+func TestDo_cacheSkipsNullAndUncachedEndpoints(t *testing.T) {
+	policy := func(path string, body []byte) time.Duration {
+		if path == gohn.TOP_STORIES_URL {
+			return 0
+		}
+		return time.Minute
+	}
+	client, mux, _, teardown := setup.Init(gohn.WithCache(gohn.NewLRUCache(10), policy))
+	defer teardown()
+
+	var storiesRequests, itemRequests int32
+	mux.HandleFunc("/topstories.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&storiesRequests, 1)
+		fmt.Fprint(w, `[1, 2, 3]`)
+	})
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&itemRequests, 1)
+		fmt.Fprint(w, `null`)
+	})
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := client.Stories.GetTopIDs(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, err := client.Items.Get(ctx, 1); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	if n := atomic.LoadInt32(&storiesRequests); n != 2 {
+		t.Errorf("expected uncached endpoint to be requested 2 times, got %d", n)
+	}
+	if n := atomic.LoadInt32(&itemRequests); n != 2 {
+		t.Errorf("expected null item to be requested 2 times, got %d", n)
+	}
+}
+
+func TestCacheTTLs_Policy(t *testing.T) {
+	ttls := gohn.DefaultCacheTTLs()
+	policy := ttls.Policy()
+
+	oldItem := []byte(fmt.Sprintf(`{"id": 1, "time": %d}`, time.Now().Add(-2*ttls.OldItemAge).Unix()))
+	newItem := []byte(fmt.Sprintf(`{"id": 2, "time": %d}`, time.Now().Unix()))
+
+	tests := []struct {
+		path string
+		body []byte
+		want time.Duration
+	}{
+		{path: gohn.TOP_STORIES_URL, body: []byte(`[1]`), want: ttls.Live},
+		{path: gohn.MAX_ITEM_ID_URL, body: []byte(`1`), want: ttls.Live},
+		{path: gohn.UPDATES_URL, body: []byte(`{}`), want: ttls.Live},
+		{path: "user/jl.json", body: []byte(`{"id": "jl"}`), want: ttls.Users},
+		{path: "item/1.json", body: oldItem, want: ttls.OldItems},
+		{path: "item/2.json", body: newItem, want: ttls.Items},
+		{path: "unknown.json", body: []byte(`{}`), want: 0},
+	}
+	for _, test := range tests {
+		if got := policy(test.path, test.body); got != test.want {
+			t.Errorf("expected TTL %v for %s, got %v", test.want, test.path, got)
+		}
+	}
+}
+
+func TestNewClient_withNilCache(t *testing.T) {
+	_, err := gohn.NewClient(gohn.WithCache(nil, nil))
+	if err == nil {
+		t.Fatalf("expected error for nil cache")
+	}
+}
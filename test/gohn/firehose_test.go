@@ -0,0 +1,183 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestFirehose(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	// the max item ID is 10 at the first poll and 12 afterwards
+	var maxIDPolls int32
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&maxIDPolls, 1) == 1 {
+			fmt.Fprint(w, `10`)
+			return
+		}
+		fmt.Fprint(w, `12`)
+	})
+	// item 9 is not available the first time it is requested
+	var item9Requests int32
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		if id == 9 && atomic.AddInt32(&item9Requests, 1) == 1 {
+			fmt.Fprint(w, `null`)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d, "type": "comment"}`, id)
+	})
+
+	var mu sync.Mutex
+	var checkpoints []int
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items, err := client.Items.Firehose(ctx, &gohn.FirehoseOptions{
+		StartAfter:   7,
+		PollInterval: time.Millisecond,
+		BatchSize:    2,
+		OnCheckpoint: func(id int) {
+			mu.Lock()
+			defer mu.Unlock()
+			checkpoints = append(checkpoints, id)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []int
+	for len(got) < 5 {
+		select {
+		case item := <-items:
+			got = append(got, *item.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for items, got %v", got)
+		}
+	}
+
+	if want := []int{8, 10, 9, 11, 12}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected items %v, got %v", want, got)
+	}
+
+	cancel()
+	for range items {
+		// drain the items until the channel is closed
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	// 9 is missing after the first poll, so the checkpoint stops at 8 until it is retrieved
+	if want := []int{8, 10, 12}; !reflect.DeepEqual(checkpoints, want) {
+		t.Errorf("expected checkpoints %v, got %v", want, checkpoints)
+	}
+}
+
+func TestFirehose_skipsGapsAfterMaxRetries(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `3`)
+	})
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		if id == 2 {
+			fmt.Fprint(w, `null`)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d}`, id)
+	})
+
+	skipped := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items, err := client.Items.Firehose(ctx, &gohn.FirehoseOptions{
+		StartAfter:    1,
+		PollInterval:  time.Millisecond,
+		MaxGapRetries: 2,
+		OnError:       func(err error) { skipped <- err },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if item := <-items; *item.ID != 3 {
+		t.Errorf("expected item 3, got %d", *item.ID)
+	}
+
+	select {
+	case err := <-skipped:
+		var itemErr *gohn.ItemError
+		if !errors.As(err, &itemErr) || itemErr.ID != 2 || !errors.Is(err, gohn.ErrItemNotFound) {
+			t.Errorf("expected item 2 to be skipped, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for item 2 to be skipped")
+	}
+}
+
+func TestFirehose_startsFromMaxID(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error", http.StatusInternalServerError)
+	})
+
+	if _, err := client.Items.Firehose(context.Background(), nil); err == nil {
+		t.Errorf("expected error when the max item ID cannot be retrieved")
+	}
+}
+
+func TestFirehose_withCache(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithCache(gohn.NewLRUCache(100), nil))
+	defer teardown()
+
+	// the max item ID grows at each poll, which the Cache must not hide
+	var maxID int32
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, atomic.AddInt32(&maxID, 1))
+	})
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		fmt.Fprintf(w, `{"id": %d, "type": "comment"}`, id)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items, err := client.Items.Firehose(ctx, &gohn.FirehoseOptions{PollInterval: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []int
+	for len(got) < 3 {
+		select {
+		case item := <-items:
+			got = append(got, *item.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for items, got %v", got)
+		}
+	}
+	if want := []int{2, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected items %v, got %v", want, got)
+	}
+
+	cancel()
+	for range items {
+		// drain the items until the channel is closed
+	}
+}
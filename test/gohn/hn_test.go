@@ -1,19 +1,23 @@
 ﻿package gohntest
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
 	"github.com/alexferrari88/gohn/test/setup"
 )
 
 func TestNewClient(t *testing.T) {
-	c, err := gohn.NewClient(nil)
+	c, err := gohn.NewClient()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -26,7 +30,7 @@ func TestNewClient(t *testing.T) {
 		t.Errorf("expected user agent %v, got %v", gohn.USER_AGENT, c.UserAgent)
 	}
 
-	c2, err := gohn.NewClient(nil)
+	c2, err := gohn.NewClient()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -36,7 +40,7 @@ func TestNewClient(t *testing.T) {
 }
 
 func TestNewRequest(t *testing.T) {
-	c, err := gohn.NewClient(nil)
+	c, err := gohn.NewClient()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -62,7 +66,7 @@ func TestNewRequest(t *testing.T) {
 }
 
 func TestNewRequest_badURL(t *testing.T) {
-	c, err := gohn.NewClient(nil)
+	c, err := gohn.NewClient()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -74,6 +78,33 @@ func TestNewRequest_badURL(t *testing.T) {
 		t.Errorf("Expected URL parse error, got %+v", err)
 	}
 }
+func TestNewClient_errorForNoTrailingSlash(t *testing.T) {
+	tests := []struct {
+		rawurl    string
+		wantError bool
+	}{
+		{rawurl: "https://example.com/api", wantError: true},
+		{rawurl: "https://example.com/api/", wantError: false},
+		{rawurl: "/api/", wantError: true},
+	}
+	for _, test := range tests {
+		c, err := gohn.NewClient(gohn.WithBaseURL(test.rawurl))
+		if test.wantError {
+			var optErr *gohn.InvalidOptionError
+			if !errors.As(err, &optErr) {
+				t.Fatalf("expected InvalidOptionError for %q, got %v.", test.rawurl, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("expected error to be nil, got %v.", err)
+		}
+		if c.BaseURL.String() != test.rawurl {
+			t.Errorf("expected base url %v, got %v", test.rawurl, c.BaseURL)
+		}
+	}
+}
+
 func TestNewRequest_errorForNoTrailingSlash(t *testing.T) {
 	tests := []struct {
 		rawurl    string
@@ -82,7 +113,7 @@ func TestNewRequest_errorForNoTrailingSlash(t *testing.T) {
 		{rawurl: "https://example.com/api", wantError: true},
 		{rawurl: "https://example.com/api/", wantError: false},
 	}
-	c, err := gohn.NewClient(nil)
+	c, err := gohn.NewClient()
 	if err != nil {
 		t.Errorf("expected no error, got %v", err)
 	}
@@ -91,6 +122,7 @@ func TestNewRequest_errorForNoTrailingSlash(t *testing.T) {
 		if err != nil {
 			t.Fatalf("url.Parse returned unexpected error: %v.", err)
 		}
+		// BaseURL changed after the Client was created
 		c.BaseURL = u
 		if _, err := c.NewRequest(http.MethodGet, "test"); test.wantError && err == nil {
 			t.Fatalf("expected error to be returned.")
@@ -100,6 +132,69 @@ func TestNewRequest_errorForNoTrailingSlash(t *testing.T) {
 	}
 }
 
+func TestNewClient_options(t *testing.T) {
+	httpClient := &http.Client{Timeout: time.Minute}
+	transport := &http.Transport{}
+	c, err := gohn.NewClient(
+		gohn.WithHTTPClient(httpClient),
+		gohn.WithTimeout(time.Second),
+		gohn.WithTransport(transport),
+		gohn.WithUserAgent("test-agent"),
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if c.UserAgent != "test-agent" {
+		t.Errorf("expected user agent %v, got %v", "test-agent", c.UserAgent)
+	}
+	if got := c.GetHTTPClient(); got.Timeout != time.Second || got.Transport != transport {
+		t.Errorf("expected timeout and transport to be set, got %v and %v", got.Timeout, got.Transport)
+	}
+	if httpClient.Timeout != time.Minute || httpClient.Transport != nil {
+		t.Errorf("expected the given http.Client not to be modified")
+	}
+
+	if _, err := gohn.NewClient(gohn.WithTimeout(0)); err == nil {
+		t.Errorf("expected error for zero timeout")
+	}
+	if _, err := gohn.NewClient(gohn.WithHTTPClient(nil)); err == nil {
+		t.Errorf("expected error for nil http client")
+	}
+}
+
+func TestDo_loggerAndMetrics(t *testing.T) {
+	var logs bytes.Buffer
+	var metrics []gohn.RequestMetrics
+	policy := gohn.DefaultRetryPolicy()
+	policy.MaxAttempts = 2
+	policy.InitialBackoff = time.Millisecond
+	c, mux, _, teardown := setup.Init(
+		gohn.WithLogger(log.New(&logs, "", 0)),
+		gohn.WithMetrics(func(m gohn.RequestMetrics) { metrics = append(metrics, m) }),
+		gohn.WithRetryPolicy(policy),
+	)
+	defer teardown()
+
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	req, _ := c.NewRequest("GET", ".")
+	if _, err := c.Do(context.Background(), req, nil); err == nil {
+		t.Fatalf("expected error to be returned")
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 metrics report, got %d", len(metrics))
+	}
+	if m := metrics[0]; m.Attempts != 2 || m.StatusCode != http.StatusServiceUnavailable || m.Err == nil || m.FromCache {
+		t.Errorf("unexpected metrics %+v", m)
+	}
+	if !strings.Contains(logs.String(), "retrying") || !strings.Contains(logs.String(), "failed:") {
+		t.Errorf("expected retry and failure to be logged, got %q", logs.String())
+	}
+}
+
 func TestDo(t *testing.T) {
 	c, mux, _, teardown := setup.Init()
 	defer teardown()
@@ -7,8 +7,9 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
-	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
 	"github.com/alexferrari88/gohn/test/setup"
@@ -446,9 +447,7 @@ func TestFetchAllDescendants_Processor_excludeKids(t *testing.T) {
 	})
 
 	ctx := context.Background()
-	processor := func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
-		wg.Add(1)
-		defer wg.Done()
+	processor := func(item *gohn.Item) (bool, error) {
 		if item.ID != nil && *item.ID == idToExclude {
 			return true, errors.New("mock error")
 		}
@@ -511,9 +510,7 @@ func TestFetchAllDescendants_Processor_includeKids(t *testing.T) {
 	})
 
 	ctx := context.Background()
-	processor := func(item *gohn.Item, wg *sync.WaitGroup) (bool, error) {
-		wg.Add(1)
-		defer wg.Done()
+	processor := func(item *gohn.Item) (bool, error) {
 		if item.ID != nil && *item.ID == idToExclude {
 			return false, errors.New("mock error")
 		}
@@ -601,3 +598,306 @@ func TestFetchAllDescendants_NumDescendantsLowerThanActual(t *testing.T) {
 		}
 	}
 }
+
+// handleItems registers a handler on mux for each item in fixtures,
+// which maps item IDs to their JSON representation.
+func handleItems(mux *http.ServeMux, fixtures map[int]string) {
+	for id, body := range fixtures {
+		body := body
+		mux.HandleFunc(fmt.Sprintf("/item/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, body)
+		})
+	}
+}
+
+// treeFixtures is a story (1) with comments 2, 3, 4 and replies 5, 6 (to 2) and 7 (to 3).
+var treeFixtures = map[int]string{
+	1: `{"id": 1, "type": "story", "kids": [2, 3, 4], "descendants": 6}`,
+	2: `{"id": 2, "type": "comment", "kids": [5, 6], "parent": 1}`,
+	3: `{"id": 3, "type": "comment", "kids": [7], "parent": 1}`,
+	4: `{"id": 4, "type": "comment", "parent": 1}`,
+	5: `{"id": 5, "type": "comment", "parent": 2}`,
+	6: `{"id": 6, "type": "comment", "parent": 2}`,
+	7: `{"id": 7, "type": "comment", "parent": 3}`,
+}
+
+func TestFetchDescendants_maxDepth(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	ctx := context.Background()
+	story, _ := client.Items.Get(ctx, 1)
+	res, err := client.Items.FetchDescendants(ctx, story, &gohn.FetchOptions{MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := res.Items
+
+	var ids []int
+	for id := range got {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	if !reflect.DeepEqual(ids, []int{2, 3, 4}) {
+		t.Errorf("expected only the top level comments, got %v", ids)
+	}
+}
+
+func TestFetchDescendants_maxItems(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	ctx := context.Background()
+	story, _ := client.Items.Get(ctx, 1)
+	res, err := client.Items.FetchDescendants(ctx, story, &gohn.FetchOptions{MaxItems: 4, Workers: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Items) != 4 {
+		t.Errorf("expected 4 items, got %d", len(res.Items))
+	}
+}
+
+func TestFetchDescendants_boundedWorkers(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	kids := make([]int, 50)
+	for i := range kids {
+		kids[i] = i + 2
+	}
+	story := &gohn.Item{ID: new(int), Kids: &kids}
+
+	var inFlight, peak int32
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		fmt.Fprintf(w, `{"id": %d, "type": "comment"}`, id)
+	})
+
+	workers := 3
+	res, err := client.Items.FetchDescendants(context.Background(), story, &gohn.FetchOptions{Workers: workers})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(res.Items) != len(kids) {
+		t.Errorf("expected %d items, got %d", len(kids), len(res.Items))
+	}
+	if p := atomic.LoadInt32(&peak); p > int32(workers) {
+		t.Errorf("expected at most %d requests in flight, got %d", workers, p)
+	}
+}
+
+// A stale Descendants count or a malformed tree must not prevent the fetching from ending.
+func TestFetchDescendants_terminatesOnCycleAndStaleDescendants(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "kids": [3], "parent": 1}`,
+		3: `{"id": 3, "type": "comment", "kids": [2, 1], "parent": 2}`,
+		4: `null`,
+	})
+
+	id, descendants := 1, 100
+	story := &gohn.Item{ID: &id, Kids: &[]int{2, 4}, Descendants: &descendants}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	got, err := client.Items.FetchAllDescendants(ctx, story, func(item *gohn.Item) (bool, error) {
+		return false, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected 2 items, got %d", len(got))
+	}
+}
+
+func TestFetchDescendants_reportsFailures(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "kids": [5, 6], "parent": 1}`,
+		3: `null`,
+		4: `{"id": 4, "type": "comment", "parent": 1}`,
+		5: `{"id": 5, "type": "comment", "parent": 2}`,
+	})
+	mux.HandleFunc("/item/6.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "error", http.StatusInternalServerError)
+	})
+
+	id := 1
+	story := &gohn.Item{ID: &id, Kids: &[]int{2, 3, 4}}
+	res, err := client.Items.FetchDescendants(context.Background(), story, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Complete() {
+		t.Errorf("expected the result not to be complete")
+	}
+	if len(res.Items) != 3 {
+		t.Errorf("expected 3 items, got %d", len(res.Items))
+	}
+	if len(res.Failed) != 2 {
+		t.Fatalf("expected 2 failures, got %v", res.Failed)
+	}
+
+	if f := res.Failed[0]; f.ID != 3 || f.Parent != 1 || !errors.Is(f, gohn.ErrItemNotFound) {
+		t.Errorf("expected item 3 of parent 1 not to be found, got %+v", f)
+	}
+	var respErr *gohn.ResponseError
+	if f := res.Failed[1]; f.ID != 6 || f.Parent != 2 || !errors.As(f, &respErr) {
+		t.Errorf("expected item 6 of parent 2 to fail with a ResponseError, got %+v", f)
+	}
+}
+
+func TestFetchDescendants_strict(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "parent": 1}`,
+		3: `null`,
+	})
+
+	id := 1
+	story := &gohn.Item{ID: &id, Kids: &[]int{2, 3}}
+	res, err := client.Items.FetchDescendants(context.Background(), story, &gohn.FetchOptions{Strict: true, Workers: 1})
+
+	var itemErr *gohn.ItemError
+	if !errors.As(err, &itemErr) || itemErr.ID != 3 {
+		t.Fatalf("expected an ItemError for item 3, got %v", err)
+	}
+	if res == nil || len(res.Failed) != 1 {
+		t.Fatalf("expected a partial result with 1 failure, got %+v", res)
+	}
+}
+
+func TestFetchDescendants_stopFetching(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	id := 1
+	story := &gohn.Item{ID: &id, Kids: &[]int{2, 3, 4}}
+	stopAt3 := func(item *gohn.Item) (bool, error) {
+		if *item.ID == 3 {
+			return true, gohn.ErrStopFetching
+		}
+		return false, nil
+	}
+	res, err := client.Items.FetchDescendants(context.Background(), story, &gohn.FetchOptions{Workers: 1, Processor: stopAt3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !res.Stopped {
+		t.Errorf("expected the result to be stopped")
+	}
+	if _, ok := res.Items[3]; ok {
+		t.Errorf("expected item 3 to be excluded")
+	}
+	// with a single worker, only 2 is retrieved before 3
+	if len(res.Items) != 1 || res.Items[2] == nil {
+		t.Errorf("expected only item 2, got %v", res.Items)
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		switch id {
+		case 4:
+			fmt.Fprint(w, `null`)
+		case 5:
+			http.Error(w, "error", http.StatusInternalServerError)
+		default:
+			fmt.Fprintf(w, `{"id": %d, "type": "story"}`, id)
+		}
+	})
+
+	ids := []int{3, 1, 4, 3, 5, 2}
+	items, err := client.Items.GetMany(context.Background(), ids, &gohn.GetManyOptions{Concurrency: 2})
+
+	var errs gohn.ItemErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected ItemErrors, got %v", err)
+	}
+	if len(errs) != 2 || errs[0].ID != 4 || !errors.Is(errs[0], gohn.ErrItemNotFound) || errs[1].ID != 5 {
+		t.Errorf("expected errors for items 4 and 5, got %v", errs)
+	}
+	if len(items) != len(ids) {
+		t.Fatalf("expected %d items, got %d", len(ids), len(items))
+	}
+	for i, id := range ids {
+		if id == 4 || id == 5 {
+			if items[i] != nil {
+				t.Errorf("expected item %d to be nil, got %v", id, items[i])
+			}
+			continue
+		}
+		if items[i] == nil || *items[i].ID != id {
+			t.Errorf("expected item %d at position %d, got %v", id, i, items[i])
+		}
+	}
+	if items[0] != items[3] {
+		t.Errorf("expected repeated IDs to share the same item")
+	}
+	if n := atomic.LoadInt32(&requests); n != 5 {
+		t.Errorf("expected 5 requests, got %d", n)
+	}
+}
+
+func TestGetMany_processor(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	processor := func(item *gohn.Item) (bool, error) {
+		if *item.ID%2 == 0 {
+			return false, errors.New("even")
+		}
+		return false, nil
+	}
+	items, err := client.Items.GetMany(context.Background(), []int{2, 3, 4, 5}, &gohn.GetManyOptions{Processor: processor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i, want := range []bool{false, true, false, true} {
+		if got := items[i] != nil; got != want {
+			t.Errorf("expected presence of item at position %d to be %v, got %v", i, want, got)
+		}
+	}
+}
+
+func TestGetMany_canceled(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	items, err := client.Items.GetMany(ctx, []int{1, 2, 3}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if items != nil {
+		t.Errorf("expected no items, got %v", items)
+	}
+}
@@ -0,0 +1,137 @@
+package gohntest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func decodeItem(t *testing.T, data string) *gohn.Item {
+	t.Helper()
+	var item gohn.Item
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatalf("unexpected error decoding item: %v", err)
+	}
+	return &item
+}
+
+func TestItem_accessors(t *testing.T) {
+	var nilItem *gohn.Item
+	if nilItem.Kind() != "" || nilItem.GetID() != 0 || nilItem.GetTitle() != "" || nilItem.GetKids() != nil || nilItem.IsDeleted() {
+		t.Errorf("expected zero values for a nil item")
+	}
+	if kind := (&gohn.Item{}).Kind(); kind != "" {
+		t.Errorf("expected empty kind for an item without type, got %q", kind)
+	}
+
+	item := decodeItem(t, `{"id": 8863, "type": "story", "by": "dhouston", "time": 1175714200, "title": "My YC app", "url": "http://www.getdropbox.com/u/2/screencast.html", "score": 111, "descendants": 71, "kids": [8952, 9224]}`)
+	if !item.Is(gohn.ItemTypeStory) || item.Kind() != gohn.ItemTypeStory {
+		t.Errorf("expected a story, got %q", item.Kind())
+	}
+	if item.GetBy() != "dhouston" || item.GetScore() != 111 || item.GetDescendants() != 71 {
+		t.Errorf("unexpected accessor values for %+v", item)
+	}
+	if !item.GetTime().Equal(time.Unix(1175714200, 0)) {
+		t.Errorf("expected time %v, got %v", time.Unix(1175714200, 0), item.GetTime())
+	}
+	if item.GetParent() != 0 || item.GetPoll() != 0 || item.GetText() != "" || item.IsDead() {
+		t.Errorf("expected zero values for the missing fields")
+	}
+}
+
+func TestItemType_IsValid(t *testing.T) {
+	for _, kind := range []gohn.ItemType{gohn.ItemTypeStory, gohn.ItemTypeComment, gohn.ItemTypeJob, gohn.ItemTypePoll, gohn.ItemTypePollOpt} {
+		if !kind.IsValid() {
+			t.Errorf("expected %q to be valid", kind)
+		}
+	}
+	if gohn.ItemType("user").IsValid() {
+		t.Errorf("expected %q to be invalid", "user")
+	}
+}
+
+func TestNewStoryItem(t *testing.T) {
+	item := decodeItem(t, `{"id": 1, "type": "story", "by": "alice", "time": 1700000000, "title": "Hello", "url": "https://example.com", "score": 10, "descendants": 2, "kids": [2, 3]}`)
+	story, err := item.AsStory()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := &gohn.StoryItem{
+		Raw:         item,
+		ID:          1,
+		By:          "alice",
+		Time:        time.Unix(1700000000, 0),
+		Title:       "Hello",
+		URL:         "https://example.com",
+		Score:       10,
+		Descendants: 2,
+		Kids:        []int{2, 3},
+	}
+	if !reflect.DeepEqual(story, want) {
+		t.Errorf("expected %+v, got %+v", want, story)
+	}
+}
+
+func TestNewCommentItem(t *testing.T) {
+	comment, err := gohn.NewCommentItem(decodeItem(t, `{"id": 2, "type": "comment", "by": "bob", "parent": 1, "text": "Hi"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comment.ID != 2 || comment.Parent != 1 || comment.Text != "Hi" || comment.Kids != nil {
+		t.Errorf("unexpected comment %+v", comment)
+	}
+
+	if _, err := gohn.NewCommentItem(decodeItem(t, `{"id": 2, "type": "comment"}`)); err == nil {
+		t.Errorf("expected error for a comment without parent")
+	}
+}
+
+func TestNewPollOptItem(t *testing.T) {
+	opt, err := gohn.NewPollOptItem(decodeItem(t, `{"id": 11, "type": "pollopt", "poll": 10, "score": 42, "text": "Yes"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opt.Poll != 10 || opt.Score != 42 || opt.Text != "Yes" {
+		t.Errorf("unexpected pollopt %+v", opt)
+	}
+
+	if _, err := gohn.NewPollOptItem(decodeItem(t, `{"id": 11, "type": "pollopt"}`)); err == nil {
+		t.Errorf("expected error for a pollopt without poll")
+	}
+}
+
+func TestNewTypedItem_invalid(t *testing.T) {
+	tests := map[string]*gohn.Item{
+		"nil":        nil,
+		"no id":      decodeItem(t, `{"type": "job"}`),
+		"no type":    decodeItem(t, `{"id": 1}`),
+		"wrong type": decodeItem(t, `{"id": 1, "type": "story"}`),
+	}
+	for name, item := range tests {
+		item := item
+		t.Run(name, func(t *testing.T) {
+			_, err := gohn.NewJobItem(item)
+			var invalidErr gohn.InvalidItemError
+			if !errors.As(err, &invalidErr) {
+				t.Errorf("expected an InvalidItemError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetStoryIdFromComment_noType(t *testing.T) {
+	client, _, _, teardown := setup.Init()
+	defer teardown()
+
+	id := 1
+	_, err := client.Items.GetStoryIdFromComment(context.Background(), &gohn.Item{ID: &id})
+	if err == nil {
+		t.Errorf("expected error for an item without type")
+	}
+}
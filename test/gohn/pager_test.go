@@ -0,0 +1,224 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestThreadPager(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, treeFixtures)
+	ctx := context.Background()
+
+	pager, err := client.Items.NewThreadPager(decodeItem(t, treeFixtures[1]), &gohn.ThreadPagerOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !pager.HasMore() || pager.MoreReplies(1) != 3 {
+		t.Errorf("expected 3 top-level comments to load, got %d", pager.MoreReplies(1))
+	}
+
+	page, err := pager.NextPage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := storyIDs(page); !reflect.DeepEqual(got, []int{2, 3}) {
+		t.Errorf("expected first page [2 3], got %v", got)
+	}
+	if pager.MoreReplies(2) != 2 || pager.MoreReplies(3) != 1 || pager.MoreReplies(1) != 1 {
+		t.Errorf("unexpected reply counts: %d, %d, %d", pager.MoreReplies(2), pager.MoreReplies(3), pager.MoreReplies(1))
+	}
+
+	replies, err := pager.Expand(ctx, 2, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := storyIDs(replies); !reflect.DeepEqual(got, []int{5}) {
+		t.Errorf("expected replies [5], got %v", got)
+	}
+	if pager.MoreReplies(2) != 1 {
+		t.Errorf("expected 1 more reply to 2, got %d", pager.MoreReplies(2))
+	}
+
+	page, err = pager.NextPage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := storyIDs(page); !reflect.DeepEqual(got, []int{4}) {
+		t.Errorf("expected second page [4], got %v", got)
+	}
+	if pager.HasMore() {
+		t.Errorf("expected no more top-level comments")
+	}
+	if page, err := pager.NextPage(ctx); err != nil || len(page) != 0 {
+		t.Errorf("expected an empty page, got %v, %v", page, err)
+	}
+
+	// limit 0 loads all the remaining replies
+	if replies, err := pager.Expand(ctx, 2, 0); err != nil || !reflect.DeepEqual(storyIDs(replies), []int{6}) {
+		t.Errorf("expected replies [6], got %v, %v", storyIDs(replies), err)
+	}
+
+	ordered, err := pager.Story.GetOrderedCommentsIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{2, 5, 6, 3, 4}; !reflect.DeepEqual(ordered, want) {
+		t.Errorf("expected loaded comments in order %v, got %v", want, ordered)
+	}
+}
+
+func TestThreadPager_errors(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		1: `{"id": 1, "type": "story", "kids": [2, 3]}`,
+		2: `{"id": 2, "type": "comment", "parent": 1}`,
+		3: `null`,
+	})
+	ctx := context.Background()
+
+	pager, err := client.Items.NewThreadPager(decodeItem(t, `{"id": 1, "type": "story", "kids": [2, 3]}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, err := pager.NextPage(ctx)
+	var itemErrs gohn.ItemErrors
+	if !errors.As(err, &itemErrs) || len(itemErrs) != 1 || itemErrs[0].ID != 3 || itemErrs[0].Parent != 1 {
+		t.Errorf("expected comment 3 of story 1 to be reported, got %v", err)
+	}
+	if got := storyIDs(page); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("expected page [2], got %v", got)
+	}
+
+	if _, err := pager.Expand(ctx, 3, 10); err == nil {
+		t.Errorf("expected error expanding a comment that is not loaded")
+	}
+	if pager.MoreReplies(3) != 0 {
+		t.Errorf("expected no replies for a comment that is not loaded")
+	}
+}
+
+func TestThreadPager_retriesFailedComments(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "kids": [5, 6], "parent": 1}`,
+		4: `{"id": 4, "type": "comment", "parent": 1}`,
+		6: `{"id": 6, "type": "comment", "parent": 2}`,
+	})
+	// comments 3 and 5 fail the first time they are requested
+	var requests3, requests5 int32
+	failOnce := func(id int, requests *int32) {
+		mux.HandleFunc(fmt.Sprintf("/item/%d.json", id), func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(requests, 1) == 1 {
+				http.Error(w, "error", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"id": %d, "type": "comment"}`, id)
+		})
+	}
+	failOnce(3, &requests3)
+	failOnce(5, &requests5)
+	ctx := context.Background()
+
+	pager, err := client.Items.NewThreadPager(decodeItem(t, `{"id": 1, "type": "story", "kids": [2, 3, 4]}`), &gohn.ThreadPagerOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	page, err := pager.NextPage(ctx)
+	var itemErrs gohn.ItemErrors
+	if !errors.As(err, &itemErrs) || len(itemErrs) != 1 || itemErrs[0].ID != 3 {
+		t.Errorf("expected comment 3 to be reported, got %v", err)
+	}
+	if got := storyIDs(page); !reflect.DeepEqual(got, []int{2}) {
+		t.Errorf("expected page [2], got %v", got)
+	}
+	if pager.MoreReplies(1) != 2 {
+		t.Errorf("expected 2 top-level comments left, got %d", pager.MoreReplies(1))
+	}
+
+	// the failed comment comes first, then the following one
+	page, err = pager.NextPage(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := storyIDs(page); !reflect.DeepEqual(got, []int{3, 4}) {
+		t.Errorf("expected page [3 4], got %v", got)
+	}
+	if pager.HasMore() {
+		t.Errorf("expected no more top-level comments")
+	}
+
+	replies, err := pager.Expand(ctx, 2, 0)
+	if err == nil || !reflect.DeepEqual(storyIDs(replies), []int{6}) {
+		t.Errorf("expected replies [6] and an error for 5, got %v, %v", storyIDs(replies), err)
+	}
+	if pager.MoreReplies(2) != 1 {
+		t.Errorf("expected 1 more reply to 2, got %d", pager.MoreReplies(2))
+	}
+	replies, err = pager.Expand(ctx, 2, 0)
+	if err != nil || !reflect.DeepEqual(storyIDs(replies), []int{5}) {
+		t.Errorf("expected replies [5], got %v, %v", storyIDs(replies), err)
+	}
+	if pager.MoreReplies(2) != 0 {
+		t.Errorf("expected no more replies to 2, got %d", pager.MoreReplies(2))
+	}
+
+	ordered, err := pager.Story.GetOrderedCommentsIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{2, 5, 6, 3, 4}; !reflect.DeepEqual(ordered, want) {
+		t.Errorf("expected loaded comments in order %v, got %v", want, ordered)
+	}
+}
+
+func TestThreadPager_expandGrandchild(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, map[int]string{
+		2: `{"id": 2, "type": "comment", "kids": [3], "parent": 1}`,
+		3: `{"id": 3, "type": "comment", "kids": [4], "parent": 2}`,
+		4: `{"id": 4, "type": "comment", "parent": 3}`,
+		5: `{"id": 5, "type": "comment", "parent": 1}`,
+	})
+	ctx := context.Background()
+
+	pager, err := client.Items.NewThreadPager(decodeItem(t, `{"id": 1, "type": "story", "kids": [2, 5]}`), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := pager.NextPage(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []int{2, 3} {
+		if _, err := pager.Expand(ctx, id, 0); err != nil {
+			t.Fatalf("unexpected error expanding %d: %v", id, err)
+		}
+	}
+
+	positions := map[int]bool{}
+	for id, comment := range pager.Story.CommentsByIdMap {
+		if positions[*comment.Position] {
+			t.Errorf("position %d of comment %d is not unique", *comment.Position, id)
+		}
+		positions[*comment.Position] = true
+	}
+	ordered, err := pager.Story.GetOrderedCommentsIDs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{2, 3, 4, 5}; !reflect.DeepEqual(ordered, want) {
+		t.Errorf("expected loaded comments in order %v, got %v", want, ordered)
+	}
+}
@@ -0,0 +1,114 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+// pollFixtures is a poll with three options, taken from the API documentation,
+// with the scores rounded to make the percentages easy to check.
+var pollFixtures = map[int]string{
+	126809: `{"id": 126809, "type": "poll", "by": "pg", "title": "Poll: What would happen if News.YC had explicit support for polls?", "time": 1204403652, "score": 46, "descendants": 54, "kids": [126822, 126823], "parts": [126810, 126811, 126812]}`,
+	126810: `{"id": 126810, "type": "pollopt", "by": "pg", "poll": 126809, "score": 300, "text": "Yes, ban them; I'm tired of seeing Valleywag stories on News.YC."}`,
+	126811: `{"id": 126811, "type": "pollopt", "by": "pg", "poll": 126809, "score": 100, "text": "No, let them stay."}`,
+	126812: `{"id": 126812, "type": "pollopt", "by": "pg", "poll": 126809, "score": 0, "text": "I don't care."}`,
+}
+
+func TestGetPoll(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleItems(mux, pollFixtures)
+
+	poll, err := client.Items.GetPoll(context.Background(), 126809)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if poll.Poll.ID != 126809 || poll.Poll.By != "pg" {
+		t.Errorf("unexpected poll %+v", poll.Poll)
+	}
+	if poll.TotalVotes != 400 {
+		t.Errorf("expected 400 votes, got %d", poll.TotalVotes)
+	}
+	if len(poll.Failed) != 0 {
+		t.Errorf("expected no failures, got %v", poll.Failed)
+	}
+	want := []struct {
+		id         int
+		votes      int
+		percentage float64
+	}{
+		{126810, 300, 75},
+		{126811, 100, 25},
+		{126812, 0, 0},
+	}
+	if len(poll.Options) != len(want) {
+		t.Fatalf("expected %d options, got %d", len(want), len(poll.Options))
+	}
+	for i, w := range want {
+		opt := poll.Options[i]
+		if opt.ID != w.id || opt.Score != w.votes || math.Abs(opt.Percentage-w.percentage) > 1e-9 {
+			t.Errorf("expected option %d with %d votes (%.0f%%), got %d with %d votes (%.2f%%)", w.id, w.votes, w.percentage, opt.ID, opt.Score, opt.Percentage)
+		}
+	}
+}
+
+func TestGetPoll_inconsistentOptions(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	fixtures := map[int]string{
+		1: `{"id": 1, "type": "poll", "parts": [2, 3, 4, 5]}`,
+		2: `{"id": 2, "type": "pollopt", "poll": 1, "score": 10}`,
+		3: `{"id": 3, "type": "pollopt", "poll": 99, "score": 5}`,
+		4: `{"id": 4, "type": "comment", "parent": 1}`,
+		5: `null`,
+	}
+	handleItems(mux, fixtures)
+
+	poll, err := client.Items.GetPoll(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(poll.Options) != 1 || poll.Options[0].ID != 2 || poll.Options[0].Percentage != 100 {
+		t.Errorf("expected only option 2 with 100%% of the votes, got %+v", poll.Options)
+	}
+	if poll.TotalVotes != 10 {
+		t.Errorf("expected 10 votes, got %d", poll.TotalVotes)
+	}
+
+	if len(poll.Failed) != 3 {
+		t.Fatalf("expected 3 failures, got %v", poll.Failed)
+	}
+	for i, id := range []int{3, 4, 5} {
+		if poll.Failed[i].ID != id || poll.Failed[i].Parent != 1 {
+			t.Errorf("expected failure for option %d of poll 1, got %v", id, poll.Failed[i])
+		}
+	}
+	if !errors.Is(poll.Failed[0], gohn.ErrPollMismatch) || !errors.Is(poll.Failed[1], gohn.ErrPollMismatch) {
+		t.Errorf("expected mismatches for options 3 and 4, got %v", poll.Failed)
+	}
+	if !errors.Is(poll.Failed[2], gohn.ErrItemNotFound) {
+		t.Errorf("expected option 5 not to be found, got %v", poll.Failed[2])
+	}
+}
+
+func TestGetPoll_notAPoll(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"id": 1, "type": "story"}`))
+	})
+
+	_, err := client.Items.GetPoll(context.Background(), 1)
+	var invalidErr gohn.InvalidItemError
+	if !errors.As(err, &invalidErr) {
+		t.Errorf("expected an InvalidItemError, got %v", err)
+	}
+}
@@ -0,0 +1,146 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+// countingLimiter is a RateLimiter that never blocks and counts the calls to Wait.
+type countingLimiter struct {
+	calls int32
+}
+
+func (l *countingLimiter) Wait(ctx context.Context) error {
+	atomic.AddInt32(&l.calls, 1)
+	return ctx.Err()
+}
+
+func TestTokenBucket_rate(t *testing.T) {
+	bucket, err := gohn.NewTokenBucket(100, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx := context.Background()
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if err := bucket.Wait(ctx); err != nil {
+			t.Fatalf("unexpected error waiting: %v", err)
+		}
+	}
+	// the first token is available immediately, the other 4 take 10ms each
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Errorf("expected 5 waits to take at least 40ms, took %v", elapsed)
+	}
+}
+
+func TestTokenBucket_contextCanceled(t *testing.T) {
+	bucket, err := gohn.NewTokenBucket(0.001, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := bucket.Wait(ctx); err != nil {
+		t.Fatalf("expected the first token to be available, got %v", err)
+	}
+	if err := bucket.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestNewTokenBucket_invalid(t *testing.T) {
+	if _, err := gohn.NewTokenBucket(0, 1); err == nil {
+		t.Errorf("expected error for zero rate")
+	}
+	if _, err := gohn.NewTokenBucket(1, 0); err == nil {
+		t.Errorf("expected error for zero burst")
+	}
+	if _, err := gohn.NewClient(gohn.WithMaxConcurrency(0)); err == nil {
+		t.Errorf("expected error for zero max concurrency")
+	}
+}
+
+func TestDo_rateLimiterSharedAcrossServices(t *testing.T) {
+	limiter := &countingLimiter{}
+	client, mux, _, teardown := setup.Init(gohn.WithRateLimiter(limiter))
+	defer teardown()
+
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+	mux.HandleFunc("/topstories.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[1]`)
+	})
+	mux.HandleFunc("/user/jl.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": "jl"}`)
+	})
+	mux.HandleFunc("/updates.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"items": [1], "profiles": ["jl"]}`)
+	})
+
+	ctx := context.Background()
+	if _, err := client.Items.Get(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Stories.GetTopIDs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Users.GetByUsername(ctx, "jl"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.Updates.Get(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if n := atomic.LoadInt32(&limiter.calls); n != 4 {
+		t.Errorf("expected the limiter to be called 4 times, got %d", n)
+	}
+}
+
+func TestDo_maxConcurrency(t *testing.T) {
+	maxInFlight := 2
+	client, mux, _, teardown := setup.Init(gohn.WithMaxConcurrency(maxInFlight))
+	defer teardown()
+
+	var inFlight, peak int32
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprint(w, `{"id": 1}`)
+	})
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 1; i <= 10; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			if _, err := client.Items.Get(ctx, id); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if p := atomic.LoadInt32(&peak); p > int32(maxInFlight) {
+		t.Errorf("expected at most %d requests in flight, got %d", maxInFlight, p)
+	}
+}
@@ -0,0 +1,176 @@
+package gohntest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func fastRetryPolicy(maxAttempts int) gohn.RetryPolicy {
+	policy := gohn.DefaultRetryPolicy()
+	policy.MaxAttempts = maxAttempts
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = 5 * time.Millisecond
+	return policy
+}
+
+func TestDo_retryOnServerError(t *testing.T) {
+	var attempts []gohn.RetryAttempt
+	policy := fastRetryPolicy(3)
+	policy.OnRetry = func(a gohn.RetryAttempt) {
+		attempts = append(attempts, a)
+	}
+	client, mux, _, teardown := setup.Init(gohn.WithRetryPolicy(policy))
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, `{"id": 1, "type": "story"}`)
+	})
+
+	ctx := context.Background()
+	item, err := client.Items.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error getting item: %v", err)
+	}
+	if item == nil || *item.ID != 1 {
+		t.Fatalf("expected item 1, got %v", item)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 retries to be reported, got %d", len(attempts))
+	}
+	for i, a := range attempts {
+		if a.Attempt != i+1 {
+			t.Errorf("expected attempt %d, got %d", i+1, a.Attempt)
+		}
+		var respErr *gohn.ResponseError
+		if !errors.As(a.Err, &respErr) || respErr.Response.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("expected attempt error to be a %d ResponseError, got %v", http.StatusServiceUnavailable, a.Err)
+		}
+	}
+}
+
+func TestDo_retryGivesUpAfterMaxAttempts(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithRetryPolicy(fastRetryPolicy(3)))
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "error", http.StatusInternalServerError)
+	})
+
+	ctx := context.Background()
+	_, err := client.Items.Get(ctx, 1)
+	var respErr *gohn.ResponseError
+	if !errors.As(err, &respErr) || respErr.Response.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected a %d ResponseError, got %v", http.StatusInternalServerError, err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Errorf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestDo_noRetryOnClientError(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithRetryPolicy(fastRetryPolicy(3)))
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+
+	ctx := context.Background()
+	if _, err := client.Items.Get(ctx, 1); err == nil {
+		t.Fatalf("expected error to be returned")
+	}
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 request, got %d", n)
+	}
+}
+
+func TestDo_retryHonorsRetryAfter(t *testing.T) {
+	var waits []time.Duration
+	policy := fastRetryPolicy(2)
+	policy.MaxBackoff = time.Second
+	policy.OnRetry = func(a gohn.RetryAttempt) {
+		waits = append(waits, a.Wait)
+	}
+	client, mux, _, teardown := setup.Init(gohn.WithRetryPolicy(policy))
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `42`)
+	})
+
+	ctx := context.Background()
+	got, err := client.Items.GetMaxID(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || *got != 42 {
+		t.Fatalf("expected max ID 42, got %v", got)
+	}
+	if len(waits) != 1 || waits[0] != 0 {
+		t.Errorf("expected a single retry waiting 0s as per Retry-After, got %v", waits)
+	}
+}
+
+func TestDo_retryStopsWhenContextIsCanceled(t *testing.T) {
+	policy := fastRetryPolicy(5)
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	ctx, cancel := context.WithCancel(context.Background())
+	policy.OnRetry = func(gohn.RetryAttempt) {
+		cancel()
+	}
+	client, mux, _, teardown := setup.Init(gohn.WithRetryPolicy(policy))
+	defer teardown()
+
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	if _, err := client.Items.Get(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestIsTemporaryError(t *testing.T) {
+	if gohn.IsTemporaryError(context.Canceled) {
+		t.Errorf("expected context.Canceled not to be temporary")
+	}
+	if !gohn.IsTemporaryError(fmt.Errorf("read: %w", io.ErrUnexpectedEOF)) {
+		t.Errorf("expected unexpected EOF to be temporary")
+	}
+}
+
+func TestNewClient_invalidRetryPolicy(t *testing.T) {
+	policy := gohn.DefaultRetryPolicy()
+	policy.Jitter = 2
+	if _, err := gohn.NewClient(gohn.WithRetryPolicy(policy)); err == nil {
+		t.Fatalf("expected error for invalid jitter")
+	}
+}
@@ -0,0 +1,73 @@
+package gohntest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// sortFixtures is a story (1) with comments 2, 3, 4 and replies 5, 6 (to 4) and 7 (to 2).
+// Comment 9 is a reply to 8, which was not retrieved.
+var sortFixtures = map[int]string{
+	1: `{"id": 1, "type": "story", "kids": [2, 3, 4]}`,
+	2: `{"id": 2, "type": "comment", "by": "carol", "time": 300, "kids": [7], "parent": 1}`,
+	3: `{"id": 3, "type": "comment", "by": "alice", "time": 100, "parent": 1}`,
+	4: `{"id": 4, "type": "comment", "by": "Bob", "time": 200, "kids": [5, 6], "parent": 1}`,
+	5: `{"id": 5, "type": "comment", "by": "alice", "time": 500, "parent": 4}`,
+	6: `{"id": 6, "type": "comment", "by": "alice", "time": 400, "parent": 4}`,
+	7: `{"id": 7, "type": "comment", "by": "bob", "time": 600, "parent": 2}`,
+	9: `{"id": 9, "type": "comment", "by": "dave", "time": 50, "parent": 8}`,
+}
+
+func TestStory_SortComments(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy gohn.SortStrategy
+		want     []int
+	}{
+		{"hn order", gohn.SortHNOrder, []int{2, 7, 3, 4, 5, 6, 9}},
+		{"chronological", gohn.SortChronological, []int{3, 4, 6, 5, 2, 7, 9}},
+		{"reverse chronological", gohn.SortReverseChronological, []int{2, 7, 4, 5, 6, 3, 9}},
+		// 4 has two replies, 2 has one and 3 none
+		{"subtree size", gohn.SortBySubtreeSize, []int{4, 5, 6, 2, 7, 3, 9}},
+		{"author", gohn.SortByAuthor, []int{3, 4, 6, 5, 2, 7, 9}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			story := newTestStory(t, 1, sortFixtures)
+			if err := story.SortComments(tt.strategy); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := story.GetOrderedCommentsIDs()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommentTree_Sort_keepsNesting(t *testing.T) {
+	tree, err := gohn.NewCommentTree(newTestStory(t, 1, sortFixtures))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tree.Sort(gohn.SortReverseChronological)
+	tree.Sort(gohn.SortHNOrder)
+
+	if got, want := collectIDs(tree.PreOrder), []int{2, 7, 3, 4, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v after sorting back to HN order, got %v", want, got)
+	}
+	tree.PreOrder(func(node *gohn.CommentNode) bool {
+		for _, child := range node.Children {
+			if child.Parent != node || *child.Item.Parent != *node.Item.ID {
+				t.Errorf("comment %d is not a reply to %d", *child.Item.ID, *node.Item.ID)
+			}
+		}
+		return true
+	})
+}
@@ -2,10 +2,15 @@
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/alexferrari88/gohn/pkg/gohn"
 	"github.com/alexferrari88/gohn/test/setup"
 )
 
@@ -170,3 +175,166 @@ func TestGetJobStoriesIDs(t *testing.T) {
 		}
 	}
 }
+
+// handleStoriesList serves a list of 10 stories (IDs 101 to 110) at path.
+// Even stories have a score of 10, odd ones a score of 1 and story 105 is missing.
+func handleStoriesList(mux *http.ServeMux, path string) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[101, 102, 103, 104, 105, 106, 107, 108, 109, 110]`)
+	})
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		if id == 105 {
+			fmt.Fprint(w, `null`)
+			return
+		}
+		fmt.Fprintf(w, `{"id": %d, "type": "story", "score": %d}`, id, 1+9*((id+1)%2))
+	})
+}
+
+func storyIDs(stories []*gohn.Item) []int {
+	ids := []int{}
+	for _, story := range stories {
+		ids = append(ids, *story.ID)
+	}
+	return ids
+}
+
+func TestGetStories_allLists(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	lists := map[string]func(context.Context, *gohn.ListOptions) ([]*gohn.Item, error){
+		"/topstories.json":  client.Stories.GetTopStories,
+		"/beststories.json": client.Stories.GetBestStories,
+		"/newstories.json":  client.Stories.GetNewStories,
+		"/askstories.json":  client.Stories.GetAskStories,
+		"/showstories.json": client.Stories.GetShowStories,
+		"/jobstories.json":  client.Stories.GetJobStories,
+	}
+	for path := range lists {
+		path := path
+		mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `[3, 1, 2]`)
+		})
+	}
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		fmt.Fprintf(w, `{"id": %d, "type": "story"}`, id)
+	})
+
+	ctx := context.Background()
+	for path, get := range lists {
+		got, err := get(ctx, nil)
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %v", path, err)
+		}
+		if ids := storyIDs(got); !reflect.DeepEqual(ids, []int{3, 1, 2}) {
+			t.Errorf("expected stories [3 1 2] for %s, got %v", path, ids)
+		}
+	}
+}
+
+func TestGetTopStories_offsetAndLimit(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleStoriesList(mux, "/topstories.json")
+
+	got, err := client.Stories.GetTopStories(context.Background(), &gohn.ListOptions{Offset: 1, Limit: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := storyIDs(got); !reflect.DeepEqual(ids, []int{102, 103, 104}) {
+		t.Errorf("expected stories [102 103 104], got %v", ids)
+	}
+
+	got, err = client.Stories.GetTopStories(context.Background(), &gohn.ListOptions{Offset: 20})
+	if err != nil || len(got) != 0 {
+		t.Errorf("expected no stories past the end of the list, got %v, %v", got, err)
+	}
+}
+
+func TestGetTopStories_processorFillsPage(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleStoriesList(mux, "/topstories.json")
+
+	minScore := func(item *gohn.Item) (bool, error) {
+		if item.Score == nil || *item.Score < 10 {
+			return false, errors.New("score too low")
+		}
+		return false, nil
+	}
+	got, err := client.Stories.GetTopStories(context.Background(), &gohn.ListOptions{Limit: 4, Processor: minScore})
+
+	// story 105 is missing, so the 4 even stories can only be found
+	// after going through it, which is reported as an error
+	var errs gohn.ItemErrors
+	if !errors.As(err, &errs) || len(errs) != 1 || errs[0].ID != 105 {
+		t.Errorf("expected an error for story 105, got %v", err)
+	}
+	if ids := storyIDs(got); !reflect.DeepEqual(ids, []int{102, 104, 106, 108}) {
+		t.Errorf("expected stories [102 104 106 108], got %v", ids)
+	}
+}
+
+func TestGetTopStories_processorKeepsBatchesConcurrent(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/topstories.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[1, 2, 3, 4, 5, 6, 7, 8]`)
+	})
+	var inFlight, peak int32
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		fmt.Fprintf(w, `{"id": %d, "type": "story"}`, id)
+	})
+
+	// only story 8 is kept: with batches of the missing count (1),
+	// the stories would be fetched one at a time
+	onlyLast := func(item *gohn.Item) (bool, error) {
+		if *item.ID != 8 {
+			return false, errors.New("skipped")
+		}
+		return false, nil
+	}
+	got, err := client.Stories.GetTopStories(context.Background(), &gohn.ListOptions{Limit: 1, Concurrency: 4, Processor: onlyLast})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := storyIDs(got); !reflect.DeepEqual(ids, []int{8}) {
+		t.Errorf("expected stories [8], got %v", ids)
+	}
+	if p := atomic.LoadInt32(&peak); p < 2 {
+		t.Errorf("expected the stories to be fetched concurrently, got at most %d at a time", p)
+	}
+}
+
+func TestGetTopStories_batchTrimmedToLimit(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+	handleStoriesList(mux, "/topstories.json")
+
+	// the batch of 10 stories goes past story 105, which is missing,
+	// but it is not reported since the limit is reached before it
+	got, err := client.Stories.GetTopStories(context.Background(), &gohn.ListOptions{Limit: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ids := storyIDs(got); !reflect.DeepEqual(ids, []int{101, 102, 103}) {
+		t.Errorf("expected stories [101 102 103], got %v", ids)
+	}
+}
@@ -0,0 +1,211 @@
+package gohntest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+// sseHandler returns a handler that writes each of the given events
+// as a Server-Sent Event and then keeps the connection open until the client goes away.
+func sseHandler(events ...[2]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			http.Error(w, "expected an event stream request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, event := range events {
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event[0], event[1])
+			flusher.Flush()
+		}
+		<-r.Context().Done()
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/item/1.json", sseHandler(
+		[2]string{"put", `{"path": "/", "data": {"id": 1}}`},
+		[2]string{"keep-alive", `null`},
+		[2]string{"patch", `{"path": "/", "data": {"score": 2}}`},
+		[2]string{"cancel", `"permission denied"`},
+	))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	events, err := client.Subscribe(ctx, "item/1.json", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []gohn.StreamEventType
+	for event := range events {
+		got = append(got, event.Type)
+		if event.Type == gohn.StreamPatch {
+			var data struct{ Score int }
+			if err := event.Decode(&data); err != nil || data.Score != 2 || event.Path != "/" {
+				t.Errorf("unexpected patch event %+v: %v", event, err)
+			}
+		}
+	}
+
+	want := []gohn.StreamEventType{gohn.StreamPut, gohn.StreamPatch, gohn.StreamCancel}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected events %v, got %v", want, got)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("expected the stream to be closed by the cancel event")
+	}
+}
+
+func TestItemsSubscribe(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/item/1.json", sseHandler(
+		[2]string{"put", `{"path": "/", "data": {"id": 1, "type": "story", "score": 1}}`},
+		[2]string{"patch", `{"path": "/", "data": {"score": 5, "descendants": 1}}`},
+		[2]string{"put", `{"path": "/kids", "data": [2]}`},
+	))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	items, err := client.Items.Subscribe(ctx, 1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []*gohn.Item
+	for len(got) < 3 {
+		select {
+		case item := <-items:
+			got = append(got, item)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for items, got %d", len(got))
+		}
+	}
+
+	if *got[0].Score != 1 || got[0].Kids != nil {
+		t.Errorf("unexpected first item %+v", got[0])
+	}
+	if *got[1].Score != 5 || *got[1].Descendants != 1 || *got[1].Type != "story" {
+		t.Errorf("expected patch to be merged, got %+v", got[1])
+	}
+	if got[2].Kids == nil || !reflect.DeepEqual(*got[2].Kids, []int{2}) || *got[2].Score != 5 {
+		t.Errorf("expected kids to be set, got %+v", got[2])
+	}
+
+	cancel()
+	for range items {
+		// drain the items until the channel is closed
+	}
+}
+
+func TestSubscribeMaxID_reconnects(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	var connections int32
+	mux.HandleFunc("/maxitem.json", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&connections, 1)
+		if n == 1 {
+			// the first connection ends right after the first event
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: put\ndata: {\"path\": \"/\", \"data\": 100}\n\n")
+			return
+		}
+		sseHandler([2]string{"put", `{"path": "/", "data": 101}`})(w, r)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ids, err := client.Items.SubscribeMaxID(ctx, &gohn.StreamOptions{ReconnectDelay: time.Millisecond})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []int{100, 101} {
+		select {
+		case got := <-ids:
+			if got != want {
+				t.Errorf("expected max ID %d, got %d", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for max ID %d", want)
+		}
+	}
+	if n := atomic.LoadInt32(&connections); n != 2 {
+		t.Errorf("expected 2 connections, got %d", n)
+	}
+}
+
+func TestStoriesSubscribe(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/topstories.json", sseHandler(
+		[2]string{"put", `{"path": "/", "data": [1, 2, 3]}`},
+		[2]string{"patch", `{"path": "/", "data": {"1": 4}}`},
+	))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	lists, err := client.Stories.Subscribe(ctx, gohn.TOP_STORIES_URL, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range [][]int{{1, 2, 3}, {1, 4, 3}} {
+		select {
+		case got := <-lists:
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected list %v, got %v", want, got)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for list %v", want)
+		}
+	}
+}
+
+func TestUpdatesSubscribe_maxReconnects(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/updates.json", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+
+	var errs int32
+	opts := &gohn.StreamOptions{
+		ReconnectDelay: time.Millisecond,
+		MaxReconnects:  3,
+		OnError:        func(error) { atomic.AddInt32(&errs, 1) },
+	}
+	updates, err := client.Updates.Subscribe(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case _, ok := <-updates:
+		if ok {
+			t.Errorf("expected no updates")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for the stream to be closed")
+	}
+	if n := atomic.LoadInt32(&errs); n != 3 {
+		t.Errorf("expected 3 errors, got %d", n)
+	}
+}
@@ -0,0 +1,118 @@
+package gohntest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+)
+
+// newTestStory returns a Story built from fixtures, with the item storyID as Parent.
+func newTestStory(t *testing.T, storyID int, fixtures map[int]string) *gohn.Story {
+	t.Helper()
+	story := &gohn.Story{Parent: decodeItem(t, fixtures[storyID]), CommentsByIdMap: gohn.ItemsIndex{}}
+	for id, body := range fixtures {
+		if id != storyID {
+			story.CommentsByIdMap[id] = decodeItem(t, body)
+		}
+	}
+	return story
+}
+
+func collectIDs(walk func(func(*gohn.CommentNode) bool)) []int {
+	var ids []int
+	walk(func(node *gohn.CommentNode) bool {
+		ids = append(ids, *node.Item.ID)
+		return true
+	})
+	return ids
+}
+
+func TestCommentTree(t *testing.T) {
+	tree, err := gohn.NewCommentTree(newTestStory(t, 1, treeFixtures))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got, want := collectIDs(tree.PreOrder), []int{2, 5, 6, 3, 7, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected pre-order %v, got %v", want, got)
+	}
+	if got, want := collectIDs(tree.PostOrder), []int{5, 6, 2, 7, 3, 4}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected post-order %v, got %v", want, got)
+	}
+	if got, want := collectIDs(tree.BFS), []int{2, 3, 4, 5, 6, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected BFS %v, got %v", want, got)
+	}
+	if tree.Size() != 6 || tree.Len() != 6 || len(tree.Orphans) != 0 {
+		t.Errorf("expected 6 comments and no orphans, got size %d, len %d, %d orphans", tree.Size(), tree.Len(), len(tree.Orphans))
+	}
+
+	node := tree.Find(7)
+	if node == nil {
+		t.Fatalf("expected to find comment 7")
+	}
+	if node.Depth != 2 || *node.Parent.Item.ID != 3 {
+		t.Errorf("expected comment 7 at depth 2 under 3, got depth %d", node.Depth)
+	}
+	var path []int
+	for _, n := range node.PathToRoot() {
+		path = append(path, *n.Item.ID)
+	}
+	if want := []int{7, 3}; !reflect.DeepEqual(path, want) {
+		t.Errorf("expected path %v, got %v", want, path)
+	}
+	if size := tree.Find(2).Size(); size != 3 {
+		t.Errorf("expected 3 comments under 2, got %d", size)
+	}
+	if got, want := collectIDs(tree.Find(2).PreOrder), []int{2, 5, 6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected pre-order %v, got %v", want, got)
+	}
+	if tree.Find(42) != nil {
+		t.Errorf("expected no node for a missing comment")
+	}
+}
+
+func TestCommentTree_stop(t *testing.T) {
+	tree, err := gohn.NewCommentTree(newTestStory(t, 1, treeFixtures))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var visited []int
+	tree.PreOrder(func(node *gohn.CommentNode) bool {
+		visited = append(visited, *node.Item.ID)
+		return *node.Item.ID != 5
+	})
+	if want := []int{2, 5}; !reflect.DeepEqual(visited, want) {
+		t.Errorf("expected the traversal to stop after %v, got %v", want, visited)
+	}
+}
+
+func TestCommentTree_orphans(t *testing.T) {
+	fixtures := map[int]string{
+		1: `{"id": 1, "type": "story", "kids": [2]}`,
+		2: `{"id": 2, "type": "comment", "parent": 1}`,
+		// the parent of 4 (3) was not retrieved
+		4: `{"id": 4, "type": "comment", "parent": 3, "kids": [5]}`,
+		5: `{"id": 5, "type": "comment", "parent": 4}`,
+	}
+	tree, err := gohn.NewCommentTree(newTestStory(t, 1, fixtures))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tree.Orphans) != 1 || *tree.Orphans[0].Item.ID != 4 {
+		t.Fatalf("expected comment 4 to be the only orphan, got %v", tree.Orphans)
+	}
+	if tree.Size() != 1 || tree.Len() != 3 {
+		t.Errorf("expected size 1 and len 3, got %d and %d", tree.Size(), tree.Len())
+	}
+	reply := tree.Find(5)
+	if reply == nil || reply.Parent != tree.Orphans[0] || reply.Depth != 2 {
+		t.Errorf("expected comment 5 to be a reply to the orphan, got %+v", reply)
+	}
+}
+
+func TestNewCommentTree_nilStory(t *testing.T) {
+	if _, err := gohn.NewCommentTree(nil); err == nil {
+		t.Errorf("expected error for a nil story")
+	}
+}
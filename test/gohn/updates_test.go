@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
 	"github.com/alexferrari88/gohn/test/setup"
@@ -71,3 +74,143 @@ func TestGetUpdates(t *testing.T) {
 		}
 	}
 }
+
+func TestWatchUpdates(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	polls := []string{
+		`{"items": [1, 2], "profiles": ["user1"]}`,
+		`{"items": [2, 3], "profiles": ["user1", "user2"]}`,
+	}
+	var poll int32
+	mux.HandleFunc("/updates.json", func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&poll, 1)) - 1
+		if n >= len(polls) {
+			n = len(polls) - 1
+		}
+		fmt.Fprint(w, polls[n])
+	})
+	mux.HandleFunc("/item/", func(w http.ResponseWriter, r *http.Request) {
+		var id int
+		fmt.Sscanf(r.URL.Path, "/item/%d.json", &id)
+		fmt.Fprintf(w, `{"id": %d, "type": "comment"}`, id)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Updates.Watch(ctx, 10*time.Millisecond, &gohn.WatchOptions{HydrateItems: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for len(got) < 5 {
+		select {
+		case event := <-events:
+			if event.Err != nil {
+				t.Fatalf("unexpected error event: %v", event.Err)
+			}
+			switch event.Kind {
+			case gohn.ItemChanged:
+				if event.Item == nil || *event.Item.ID != event.ItemID {
+					t.Errorf("expected item %d to be hydrated, got %v", event.ItemID, event.Item)
+				}
+				got = append(got, fmt.Sprint(event.ItemID))
+			case gohn.ProfileChanged:
+				if event.User != nil {
+					t.Errorf("expected user %s not to be hydrated", event.Username)
+				}
+				got = append(got, event.Username)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events, got %v", got)
+		}
+	}
+
+	want := []string{"1", "2", "user1", "3", "user2"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected events %v, got %v", want, got)
+	}
+
+	cancel()
+	for range events {
+		// drain the events until the channel is closed
+	}
+}
+
+func TestWatchUpdates_withCache(t *testing.T) {
+	client, mux, _, teardown := setup.Init(gohn.WithCache(gohn.NewLRUCache(100), nil))
+	defer teardown()
+
+	// item 1 and user1 change, then nothing, then they change again
+	polls := []string{
+		`{"items": [1], "profiles": ["user1"]}`,
+		`{"items": [], "profiles": []}`,
+		`{"items": [1], "profiles": ["user1"]}`,
+		`{"items": [], "profiles": []}`,
+	}
+	var poll, itemRequests, userRequests int32
+	mux.HandleFunc("/updates.json", func(w http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&poll, 1)) - 1
+		if n >= len(polls) {
+			n = len(polls) - 1
+		}
+		fmt.Fprint(w, polls[n])
+	})
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		score := atomic.AddInt32(&itemRequests, 1)
+		fmt.Fprintf(w, `{"id": 1, "type": "story", "score": %d, "time": %d}`, score, time.Now().Unix())
+	})
+	mux.HandleFunc("/user/user1.json", func(w http.ResponseWriter, r *http.Request) {
+		karma := atomic.AddInt32(&userRequests, 1)
+		fmt.Fprintf(w, `{"id": "user1", "karma": %d}`, karma)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events, err := client.Updates.Watch(ctx, 10*time.Millisecond, &gohn.WatchOptions{HydrateItems: true, HydrateUsers: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var scores, karmas []int
+	for len(scores) < 2 || len(karmas) < 2 {
+		select {
+		case event := <-events:
+			if event.Err != nil {
+				t.Fatalf("unexpected error event: %v", event.Err)
+			}
+			switch event.Kind {
+			case gohn.ItemChanged:
+				scores = append(scores, *event.Item.Score)
+			case gohn.ProfileChanged:
+				karmas = append(karmas, *event.User.Karma)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for events, got scores %v and karmas %v", scores, karmas)
+		}
+	}
+
+	// the second events carry the new versions, not the cached ones
+	if !reflect.DeepEqual(scores, []int{1, 2}) {
+		t.Errorf("expected scores [1 2], got %v", scores)
+	}
+	if !reflect.DeepEqual(karmas, []int{1, 2}) {
+		t.Errorf("expected karmas [1 2], got %v", karmas)
+	}
+
+	cancel()
+	for range events {
+		// drain the events until the channel is closed
+	}
+}
+
+func TestWatchUpdates_invalidInterval(t *testing.T) {
+	client, _, _, teardown := setup.Init()
+	defer teardown()
+
+	if _, err := client.Updates.Watch(context.Background(), 0, nil); err == nil {
+		t.Errorf("expected error for zero interval")
+	}
+}
@@ -0,0 +1,117 @@
+package processorstest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+// hnText is a comment text with all the markup used by Hacker News.
+const hnText = `&gt; Is it *fast*?<p>Yes, see <a href="https:&#x2F;&#x2F;example.com&#x2F;bench" rel="nofollow">the benchmarks</a> and <a href="https:&#x2F;&#x2F;example.com&#x2F;a&#x2F;very&#x2F;long&#x2F;path" rel="nofollow">https:&#x2F;&#x2F;example.com&#x2F;a&#x2F;very&#x2F;...</a>. It&#x27;s <i>really</i> fast:<p><pre><code>  for i := 0; i &lt; n; i++ {
+      work()
+  }
+</code></pre>
+Bye`
+
+func TestToText(t *testing.T) {
+	want := `> Is it *fast*?
+
+Yes, see the benchmarks (https://example.com/bench) and https://example.com/a/very/long/path. It's really fast:
+
+  for i := 0; i < n; i++ {
+      work()
+  }
+
+Bye`
+	if got := processors.ToText(hnText); got != want {
+		t.Errorf("unexpected text:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	want := "> Is it \\*fast\\*?\n\n" +
+		"Yes, see [the benchmarks](https://example.com/bench) and <https://example.com/a/very/long/path>. It's *really* fast:\n\n" +
+		"```\n  for i := 0; i < n; i++ {\n      work()\n  }\n```\n\n" +
+		"Bye"
+	if got := processors.ToMarkdown(hnText); got != want {
+		t.Errorf("unexpected Markdown:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestToMarkdown_escaping(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`&lt;script&gt;alert(1)&lt;&#x2F;script&gt;`, `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{`<a href="javascript:alert(1)">click</a>`, `click`},
+		{`<a href="javascript:alert(1)">javascript:alert(1)</a>`, `javascript:alert(1)`},
+		{`<a href="https://example.com/a_(b)">link</a>`, `[link](https://example.com/a_%28b%29)`},
+		{`AT&amp;T`, `AT&amp;T`},
+		{`# not a heading<p>- not a list<p>+ nor this<p>1. nor this<p>2) nor this`,
+			"\\# not a heading\n\n\\- not a list\n\n\\+ nor this\n\n1\\. nor this\n\n2\\) nor this"},
+		{"&gt; quoted\n&gt; again", "> quoted\n> again"},
+		{`1 &lt; 2 &gt; 0`, `1 &lt; 2 &gt; 0`},
+		{`2024 was good`, `2024 was good`},
+	}
+	for _, tt := range tests {
+		if got := processors.ToMarkdown(tt.in); got != tt.want {
+			t.Errorf("ToMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{`Hello<p>world`, `Hello<p>world`},
+		{`<b>bold</b> <i>italic</i>`, `bold <i>italic</i>`},
+		{`<script>alert(1)</script>`, `alert(1)`},
+		{`<a href="javascript:alert(1)" onclick="x()">link</a>`, `<a>link</a>`},
+		{`<a href="https:&#x2F;&#x2F;example.com" target="_blank">link</a>`, `<a href="https://example.com" rel="nofollow">link</a>`},
+		{`<pre><code>a &lt; b`, `<pre><code>a &lt; b</code></pre>`},
+		{`<i>unbalanced</code></i>`, `<i>unbalanced</i>`},
+		{`1 < 2 & 3 > 2`, `1 &lt; 2 &amp; 3 &gt; 2`},
+	}
+	for _, tt := range tests {
+		if got := processors.Sanitize(tt.in); got != tt.want {
+			t.Errorf("Sanitize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestHTMLToMarkdown(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mockParentID := 1
+	mockParentType := "story"
+	mockParent := &gohn.Item{ID: &mockParentID, Type: &mockParentType, Kids: &[]int{2, 3}}
+
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "comment", "text": "See <a href=\"https:&#x2F;&#x2F;example.com\">this</a><p><i>Great</i>"}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "comment"}`)
+	})
+
+	got, err := client.Items.FetchAllDescendants(context.Background(), mockParent, processors.HTMLToMarkdown())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(got))
+	}
+	want := "See [this](https://example.com)\n\n*Great*"
+	if text := *got[2].Text; text != want {
+		t.Errorf("expected text %q, got %q", want, text)
+	}
+	if got[3].Text != nil {
+		t.Errorf("expected no text for item 3, got %q", *got[3].Text)
+	}
+}
@@ -0,0 +1,150 @@
+package processorstest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"http://www.Example.com/post/", "https://example.com/post"},
+		{"https://example.com/post?utm_source=hn&b=2&a=1#comments", "https://example.com/post?a=1&b=2"},
+		{"HTTPS://example.com:443", "https://example.com"},
+		{"https://blog.example.com//", "https://blog.example.com"},
+		{"https://github.com/golang/go/blob/x/README.md?ref=main", "https://github.com/golang/go/blob/x/README.md?ref=main"},
+	}
+	for _, tt := range tests {
+		got, err := processors.CanonicalURL(tt.in)
+		if err != nil {
+			t.Errorf("CanonicalURL(%q): unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CanonicalURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	if _, err := processors.CanonicalURL("ftp://example.com"); err == nil {
+		t.Error("expected an error for an ftp URL")
+	}
+}
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := map[string]string{
+		"example.com":            "example.com",
+		"blog.example.com":       "example.com",
+		"a.b.example.co.uk":      "example.co.uk",
+		"someone.github.io":      "someone.github.io",
+		"docs.someone.github.io": "someone.github.io",
+		"localhost":              "localhost",
+		"127.0.0.1":              "127.0.0.1",
+		"::1":                    "::1",
+	}
+	for host, want := range tests {
+		if got := processors.RegistrableDomain(host); got != want {
+			t.Errorf("RegistrableDomain(%q) = %q, want %q", host, got, want)
+		}
+	}
+}
+
+func TestSite(t *testing.T) {
+	tests := map[string]string{
+		"https://www.nytimes.com/2023/article.html": "nytimes.com",
+		"https://blog.example.co.uk/post":           "example.co.uk",
+		"https://GitHub.com/Golang/go/issues/1":     "github.com/golang",
+		"https://github.com/":                       "github.com",
+		"https://x.com/someone/status/1":            "twitter.com/someone",
+		"https://youtu.be/abc":                      "youtube.com",
+		"https://someone.github.io/blog/":           "someone.github.io",
+		"http://192.168.1.20/x":                     "192.168.1.20",
+	}
+	for in, want := range tests {
+		got, err := processors.Site(in)
+		if err != nil {
+			t.Errorf("Site(%q): unexpected error: %v", in, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Site(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFilterOutDomains(t *testing.T) {
+	tests := []struct {
+		name    string
+		domains []string
+		url     string
+		keep    bool
+	}{
+		{"domain", []string{"example.com"}, "https://example.com/a", false},
+		{"subdomain", []string{"example.com"}, "https://blog.example.com/a", false},
+		{"www", []string{"www.example.com"}, "http://example.com/", false},
+		{"only subdomain", []string{"blog.example.com"}, "https://example.com/a", true},
+		{"suffix", []string{"ample.com"}, "https://example.com/a", true},
+		{"mirror", []string{"twitter.com"}, "https://x.com/someone", false},
+		{"owner", []string{"github.com/someone"}, "https://github.com/SomeOne/repo", false},
+		{"other owner", []string{"github.com/someone"}, "https://github.com/golang/go", true},
+		{"url as domain", []string{"https://example.com/"}, "https://example.com/a", false},
+		{"invalid url", []string{"example.com"}, "javascript:alert(1)", true},
+		{"ip", []string{"192.168.1.20"}, "http://192.168.1.20/x", false},
+		{"part of ip", []string{"1.20"}, "http://192.168.1.20/x", true},
+	}
+	id := 1
+	for _, tt := range tests {
+		url := tt.url
+		skipKids, err := processors.FilterOutDomains(tt.domains)(&gohn.Item{ID: &id, URL: &url})
+		if skipKids {
+			t.Errorf("%s: expected the kids not to be skipped", tt.name)
+		}
+		if keep := err == nil; keep != tt.keep {
+			t.Errorf("%s: expected keep to be %v, got error %v", tt.name, tt.keep, err)
+		}
+		_, err = processors.KeepOnlyDomains(tt.domains)(&gohn.Item{ID: &id, URL: &url})
+		if keep := err == nil; keep == tt.keep {
+			t.Errorf("%s: expected KeepOnlyDomains to keep the item: %v", tt.name, !tt.keep)
+		}
+	}
+}
+
+func TestFilterOutDuplicateURLs(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mux.HandleFunc("/item/1.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 1, "type": "story", "url": "https://example.com/article"}`)
+	})
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "story", "url": "http://www.example.com/article/?utm_source=twitter"}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "story", "url": "https://example.com/other"}`)
+	})
+	mux.HandleFunc("/item/4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 4, "type": "story", "title": "Ask HN: Anything?"}`)
+	})
+
+	ids := []int{1, 2, 3, 4}
+	opts := &gohn.GetManyOptions{Concurrency: 1, Processor: processors.FilterOutDuplicateURLs()}
+	items, err := client.Items.GetMany(context.Background(), ids, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []int
+	for _, item := range items {
+		if item != nil {
+			got = append(got, item.GetID())
+		}
+	}
+	if fmt.Sprint(got) != "[1 3 4]" {
+		t.Errorf("expected items [1 3 4], got %v", got)
+	}
+}
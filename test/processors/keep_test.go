@@ -0,0 +1,90 @@
+package processorstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestKeepOnlyUsers(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mockParentID := 1
+	mockParentType := "story"
+	mockParent := &gohn.Item{ID: &mockParentID, Type: &mockParentType, Kids: &[]int{2, 3}}
+
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "comment", "by": "bob", "kids": [4]}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "comment", "by": "alice"}`)
+	})
+	mux.HandleFunc("/item/4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 4, "type": "comment", "by": "alice"}`)
+	})
+
+	got, err := client.Items.FetchAllDescendants(context.Background(), mockParent, processors.KeepOnlyUsers([]string{"alice"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the reply of alice to bob is kept
+	if len(got) != 2 || got[3] == nil || got[4] == nil {
+		t.Errorf("expected items 3 and 4, got %v", got)
+	}
+}
+
+func TestKeepOnly(t *testing.T) {
+	story := `{"id": 1, "type": "story", "by": "alice", "title": "Show HN: A Go parser", "url": "https://blog.example.com/parser", "score": 42, "time": 1700000000}`
+	comment := `{"id": 2, "type": "comment", "by": "bob", "text": "Nice parser", "parent": 1, "time": 1700003600}`
+	job := `{"id": 3, "type": "job", "by": "carol", "title": "Hiring", "url": "https://www.jobs.org/", "score": 1, "time": 1700007200}`
+
+	tests := []struct {
+		name      string
+		processor gohn.ItemProcessor
+		item      string
+		keep      bool
+	}{
+		{"users", processors.KeepOnlyUsers([]string{"alice", "bob"}), comment, true},
+		{"users other", processors.KeepOnlyUsers([]string{"alice"}), comment, false},
+		{"users none", processors.KeepOnlyUsers(nil), story, false},
+		{"matching", processors.KeepOnlyMatching(processors.FieldTitle, processors.Words("go")), story, true},
+		{"matching other field", processors.KeepOnlyMatching(processors.FieldText, processors.Words("go")), story, false},
+		{"domains", processors.KeepOnlyDomains([]string{"example.com"}), story, true},
+		{"domains subdomain only", processors.KeepOnlyDomains([]string{"other.example.com"}), story, false},
+		{"domains www", processors.KeepOnlyDomains([]string{"WWW.jobs.org"}), job, true},
+		{"domains suffix", processors.KeepOnlyDomains([]string{"ample.com"}), story, false},
+		{"domains without url", processors.KeepOnlyDomains([]string{"example.com"}), comment, false},
+		{"types", processors.KeepOnlyTypes(gohn.ItemTypeStory, gohn.ItemTypeJob), job, true},
+		{"types other", processors.KeepOnlyTypes(gohn.ItemTypeStory), comment, false},
+		{"between", processors.KeepOnlyBetween(time.Unix(1700000000, 0), time.Unix(1700003600, 0)), comment, true},
+		{"between after", processors.KeepOnlyBetween(time.Unix(1700000000, 0), time.Unix(1700003600, 0)), job, false},
+		{"between before", processors.KeepOnlyBetween(time.Unix(1700000001, 0), time.Time{}), story, false},
+		{"between unbounded", processors.KeepOnlyBetween(time.Time{}, time.Time{}), job, true},
+		{"score", processors.KeepOnlyScoreBetween(10, 100), story, true},
+		{"score too high", processors.KeepOnlyScoreBetween(0, 10), story, false},
+		{"score no upper bound", processors.KeepOnlyScoreBetween(42, 0), story, true},
+		{"score too low", processors.KeepOnlyScoreBetween(10, 0), job, false},
+		{"score without score", processors.KeepOnlyScoreBetween(0, 0), comment, false},
+	}
+	for _, tt := range tests {
+		var item gohn.Item
+		if err := json.Unmarshal([]byte(tt.item), &item); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		skipKids, err := tt.processor(&item)
+		if skipKids {
+			t.Errorf("%s: expected the kids not to be skipped", tt.name)
+		}
+		if keep := err == nil; keep != tt.keep {
+			t.Errorf("%s: expected keep to be %v, got error %v", tt.name, tt.keep, err)
+		}
+	}
+}
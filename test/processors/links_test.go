@@ -0,0 +1,117 @@
+package processorstest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"https://Example.com", "https://example.com/"},
+		{"HTTP://example.com:80/a?b=2&a=1#section", "http://example.com/a?a=1&b=2"},
+		{"https://example.com:8443/a", "https://example.com:8443/a"},
+		{"https://example.com/post?utm_source=hn&utm_medium=social&id=7&fbclid=abc&ref_src=twsrc", "https://example.com/post?id=7"},
+		{"https://github.com/golang/go/tree/x?ref=main", "https://github.com/golang/go/tree/x?ref=main"},
+		{"item?id=123", "https://news.ycombinator.com/item?id=123"},
+		{"/user?id=pg", "https://news.ycombinator.com/user?id=pg"},
+	}
+	for _, tt := range tests {
+		got, err := processors.NormalizeURL(tt.in)
+		if err != nil {
+			t.Errorf("NormalizeURL(%q): unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{"javascript:alert(1)", "mailto:pg@example.com", "https://"} {
+		if got, err := processors.NormalizeURL(in); err == nil {
+			t.Errorf("NormalizeURL(%q) = %q, expected error", in, got)
+		}
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	var item gohn.Item
+	data := `{"id": 1, "type": "story", "url": "https://example.com/?utm_source=hn", "text": "See <a href=\"https:&#x2F;&#x2F;example.com&#x2F;\">it</a>, <a href=\"item?id=2\">this</a> and <a href=\"mailto:a@b.c\">me</a>"}`
+	if err := json.Unmarshal([]byte(data), &item); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"https://example.com/", "https://news.ycombinator.com/item?id=2"}
+	if got := processors.ExtractLinks(&item); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := processors.ExtractLinks(nil); got != nil {
+		t.Errorf("expected no links for a nil item, got %v", got)
+	}
+}
+
+func TestLinkIndex(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mockParentID := 1
+	mockParentType := "story"
+	mockParentURL := "https://blog.example.com/post"
+	mockParent := &gohn.Item{ID: &mockParentID, Type: &mockParentType, URL: &mockParentURL, Kids: &[]int{2, 3, 4}}
+
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "comment", "text": "Also <a href=\"https:&#x2F;&#x2F;www.golang.org&#x2F;doc\">golang.org/doc</a>"}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "comment", "text": "<a href=\"https:&#x2F;&#x2F;golang.org&#x2F;doc?utm_campaign=x\">docs</a> and <a href=\"https:&#x2F;&#x2F;golang.org&#x2F;blog\">blog</a>"}`)
+	})
+	mux.HandleFunc("/item/4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 4, "type": "comment", "text": "No links"}`)
+	})
+
+	index := processors.NewLinkIndex()
+	index.Add(mockParent)
+	if _, err := client.Items.FetchAllDescendants(context.Background(), mockParent, index.Processor()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wantURLs := []processors.LinkMentions{
+		{Key: "https://blog.example.com/post", ItemIDs: []int{1}},
+		{Key: "https://golang.org/blog", ItemIDs: []int{3}},
+		{Key: "https://golang.org/doc", ItemIDs: []int{3}},
+		{Key: "https://www.golang.org/doc", ItemIDs: []int{2}},
+	}
+	if got := index.URLs(); !reflect.DeepEqual(got, wantURLs) {
+		t.Errorf("expected URLs %v, got %v", wantURLs, got)
+	}
+	wantDomains := []processors.LinkMentions{
+		{Key: "golang.org", ItemIDs: []int{2, 3}},
+		{Key: "blog.example.com", ItemIDs: []int{1}},
+	}
+	if got := index.Domains(); !reflect.DeepEqual(got, wantDomains) {
+		t.Errorf("expected domains %v, got %v", wantDomains, got)
+	}
+	if got := index.Mentions("https://GOLANG.org/doc#intro"); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("expected mentions [3], got %v", got)
+	}
+}
+
+func TestIndexStory(t *testing.T) {
+	id, kidID := 1, 2
+	url, text := "https://example.com", `<a href="https://example.com">example</a>`
+	story := &gohn.Story{
+		Parent:          &gohn.Item{ID: &id, URL: &url},
+		CommentsByIdMap: gohn.ItemsIndex{kidID: &gohn.Item{ID: &kidID, Text: &text}},
+	}
+	if got := processors.IndexStory(story).Mentions(url); !reflect.DeepEqual(got, []int{1, 2}) {
+		t.Errorf("expected mentions [1 2], got %v", got)
+	}
+}
@@ -0,0 +1,107 @@
+package processorstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher processors.Matcher
+		in      string
+		match   string
+		ok      bool
+	}{
+		{"word", processors.Words("go"), "I like Go!", "Go", true},
+		{"word start", processors.Words("go"), "go is fun", "go", true},
+		{"word inside word", processors.Words("go"), "this is good", "", false},
+		{"word with underscore", processors.Words("go"), "go_lang", "", false},
+		{"words any", processors.Words("rust", "zig"), "Zig and Rust", "Zig", true},
+		{"word with symbols", processors.Words("c++"), "I write C++ daily", "C++", true},
+		{"phrase", processors.Phrases("machine learning"), "about Machine\n learning.", "Machine\n learning", true},
+		{"phrase partial", processors.Phrases("machine learning"), "machine learnings", "", false},
+		{"no phrases", processors.Phrases(" "), "anything", "", false},
+		{"regexp", processors.Regexp(regexp.MustCompile(`v\d+\.\d+`)), "released v1.21 today", "v1.21", true},
+		{"regexp case sensitive", processors.Regexp(regexp.MustCompile(`Go`)), "go", "", false},
+		{"nil regexp", processors.Regexp(nil), "anything", "", false},
+		{"glob", processors.Glob("https://*.github.io/*"), "https://User.GitHub.io/blog", "https://User.GitHub.io/blog", true},
+		{"glob whole field", processors.Glob("github.com"), "https://github.com", "", false},
+		{"glob single char", processors.Glob("v?"), "v2", "v2", true},
+	}
+	for _, tt := range tests {
+		match, ok := tt.matcher.Find(tt.in)
+		if ok != tt.ok || match != tt.match {
+			t.Errorf("%s: Find(%q) = %q, %v, want %q, %v", tt.name, tt.in, match, ok, tt.match, tt.ok)
+		}
+	}
+}
+
+func TestRegexp_nil(t *testing.T) {
+	if s := processors.Regexp(nil).String(); s != "regexp <nil>" {
+		t.Errorf("expected regexp <nil>, got %s", s)
+	}
+}
+
+func TestField_String(t *testing.T) {
+	if s := (processors.FieldTitle | processors.FieldAuthor).String(); s != "title|author" {
+		t.Errorf("expected title|author, got %s", s)
+	}
+	if s := processors.AllFields.String(); s != "title|text|url|author" {
+		t.Errorf("expected all the fields, got %s", s)
+	}
+}
+
+func TestFilterOutMatching(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mockParentID := 1
+	mockParentType := "story"
+	mockParent := &gohn.Item{ID: &mockParentID, Type: &mockParentType, Kids: &[]int{2, 3, 4, 5}}
+
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "comment", "by": "alice", "text": "This is <i>good</i>"}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "comment", "by": "bob", "text": "I use <i>Go</i> at work"}`)
+	})
+	mux.HandleFunc("/item/4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 4, "type": "comment", "by": "gopher", "text": "Nice"}`)
+	})
+	mux.HandleFunc("/item/5.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 5, "type": "comment", "by": "carol", "text": "Unrelated"}`)
+	})
+
+	filter := processors.FilterOutMatching(processors.FieldText|processors.FieldAuthor,
+		processors.Words("go"), processors.Glob("go*"))
+	got, err := client.Items.FetchAllDescendants(context.Background(), mockParent, filter)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[2] == nil || got[5] == nil {
+		t.Errorf("expected items 2 and 5, got %v", got)
+	}
+
+	text := "Learning go"
+	_, err = filter(&gohn.Item{ID: &mockParentID, Text: &text})
+	var matchErr *processors.MatchError
+	if !errors.As(err, &matchErr) {
+		t.Fatalf("expected a MatchError, got %v", err)
+	}
+	want := processors.MatchError{ItemID: 1, Field: processors.FieldText, Rule: `words "go"`, Match: "go"}
+	if *matchErr != want {
+		t.Errorf("expected %+v, got %+v", want, *matchErr)
+	}
+	if msg := matchErr.Error(); msg != `item 1: text matches words "go" ("go")` {
+		t.Errorf("unexpected message %q", msg)
+	}
+}
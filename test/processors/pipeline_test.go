@@ -0,0 +1,129 @@
+package processorstest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+	"github.com/alexferrari88/gohn/test/setup"
+)
+
+func always(d processors.Decision) processors.Rule {
+	return func(*gohn.Item) processors.Decision { return d }
+}
+
+func TestCombinators(t *testing.T) {
+	keep, drop, dropSubtree, stop := always(processors.Keep), always(processors.Drop), always(processors.DropSubtree), always(processors.Stop)
+	isStory := func(item *gohn.Item) bool { return item.Is(gohn.ItemTypeStory) }
+
+	storyType := "story"
+	story := &gohn.Item{Type: &storyType}
+
+	tests := []struct {
+		name string
+		rule processors.Rule
+		want processors.Decision
+	}{
+		{"and all keep", processors.And(keep, keep), processors.Keep},
+		{"and first non keep", processors.And(keep, dropSubtree, drop), processors.DropSubtree},
+		{"and empty", processors.And(), processors.Keep},
+		{"or one keeps", processors.Or(drop, keep), processors.Keep},
+		{"or least severe", processors.Or(dropSubtree, drop), processors.Drop},
+		{"or empty", processors.Or(), processors.Drop},
+		{"not keep", processors.Not(keep), processors.Drop},
+		{"not drop subtree", processors.Not(dropSubtree), processors.Keep},
+		{"not stop", processors.Not(stop), processors.Stop},
+		{"sequence most severe", processors.Sequence(drop, keep, dropSubtree), processors.DropSubtree},
+		{"sequence stop", processors.Sequence(drop, stop), processors.Stop},
+		{"if then", processors.If(isStory, drop, keep), processors.Drop},
+		{"if nil otherwise", processors.If(func(*gohn.Item) bool { return false }, drop, nil), processors.Keep},
+	}
+	for _, tt := range tests {
+		if got := tt.rule(story); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestSequence_appliesAllRules(t *testing.T) {
+	var calls int
+	count := func(*gohn.Item) processors.Decision {
+		calls++
+		return processors.Keep
+	}
+	processors.Sequence(always(processors.DropSubtree), count)(&gohn.Item{})
+	processors.And(always(processors.DropSubtree), count)(&gohn.Item{})
+	if calls != 1 {
+		t.Errorf("expected only Sequence to apply the rules after a drop, got %d calls", calls)
+	}
+}
+
+func TestFromProcessor(t *testing.T) {
+	deleted := true
+	rule := processors.FromProcessor(processors.FilterOutDeleted())
+	if d := rule(&gohn.Item{Deleted: &deleted}); d != processors.Drop {
+		t.Errorf("expected deleted items to be dropped, got %v", d)
+	}
+	if d := rule(&gohn.Item{}); d != processors.Keep {
+		t.Errorf("expected other items to be kept, got %v", d)
+	}
+
+	skip, err := processors.Rule(always(processors.Drop)).Processor()(&gohn.Item{})
+	if skip || !errors.Is(err, processors.ErrDropped) {
+		t.Errorf("expected Drop to exclude only the item, got %v, %v", skip, err)
+	}
+	skip, err = processors.Rule(always(processors.Stop)).Processor()(&gohn.Item{})
+	if !skip || !errors.Is(err, gohn.ErrStopFetching) {
+		t.Errorf("expected Stop to return ErrStopFetching, got %v, %v", skip, err)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	client, mux, _, teardown := setup.Init()
+	defer teardown()
+
+	mockParentID := 1
+	mockParentType := "story"
+	mockParent := &gohn.Item{ID: &mockParentID, Type: &mockParentType, Kids: &[]int{2, 3, 4}}
+
+	mux.HandleFunc("/item/2.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 2, "type": "comment", "by": "spammer", "kids": [5], "text": "buy now"}`)
+	})
+	mux.HandleFunc("/item/3.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 3, "type": "comment", "deleted": true, "kids": [6]}`)
+	})
+	mux.HandleFunc("/item/4.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 4, "type": "comment", "by": "alice", "text": "a &amp; b"}`)
+	})
+	mux.HandleFunc("/item/5.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 5, "type": "comment", "by": "bob", "text": "reply"}`)
+	})
+	mux.HandleFunc("/item/6.json", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id": 6, "type": "comment", "by": "carol", "text": "reply"}`)
+	})
+
+	notSpam := func(item *gohn.Item) processors.Decision {
+		if item.GetBy() == "spammer" {
+			return processors.DropSubtree
+		}
+		return processors.Keep
+	}
+	pipeline := processors.NewPipeline(notSpam).
+		ThenProcessor(processors.FilterOutDeleted(), processors.UnescapeHTML())
+
+	got, err := client.Items.FetchAllDescendants(context.Background(), mockParent, pipeline.Processor())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// 2 is dropped with its reply, 3 without its reply
+	if len(got) != 2 || got[4] == nil || got[6] == nil {
+		t.Fatalf("expected items 4 and 6, got %v", got)
+	}
+	if *got[4].Text != "a & b" {
+		t.Errorf("expected the text of 4 to be unescaped, got %q", *got[4].Text)
+	}
+}
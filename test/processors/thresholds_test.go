@@ -0,0 +1,80 @@
+package processorstest
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/alexferrari88/gohn/pkg/gohn"
+	"github.com/alexferrari88/gohn/pkg/processors"
+)
+
+func TestThresholds(t *testing.T) {
+	created := time.Unix(1700000000, 0)
+	now := func() time.Time { return created.Add(2 * time.Hour) }
+	story := `{"id": 1, "type": "story", "score": 50, "descendants": 12, "time": 1700000000}`
+	comment := `{"id": 2, "type": "comment", "parent": 1, "time": 1700000000}`
+
+	tests := []struct {
+		name      string
+		processor gohn.ItemProcessor
+		item      string
+		keep      bool
+	}{
+		{"score below", processors.FilterOutScoreBelow(51), story, false},
+		{"score not below", processors.FilterOutScoreBelow(50), story, true},
+		{"score above", processors.FilterOutScoreAbove(49), story, false},
+		{"score not above", processors.FilterOutScoreAbove(50), story, true},
+		{"score without score", processors.FilterOutScoreBelow(1), comment, true},
+		{"descendants below", processors.FilterOutDescendantsBelow(13), story, false},
+		{"descendants not below", processors.FilterOutDescendantsBelow(12), story, true},
+		{"descendants above", processors.FilterOutDescendantsAbove(11), story, false},
+		{"descendants without descendants", processors.FilterOutDescendantsAbove(0), comment, true},
+		{"older", processors.FilterOutOlderThan(time.Hour, now), story, false},
+		{"not older", processors.FilterOutOlderThan(2*time.Hour, now), story, true},
+		{"newer", processors.FilterOutNewerThan(3*time.Hour, now), comment, false},
+		{"not newer", processors.FilterOutNewerThan(2*time.Hour, now), comment, true},
+		{"slower", processors.FilterOutSlowerThan(26, now), story, false},
+		{"not slower", processors.FilterOutSlowerThan(25, now), story, true},
+		{"slower without score", processors.FilterOutSlowerThan(1, now), comment, true},
+	}
+	for _, tt := range tests {
+		var item gohn.Item
+		if err := json.Unmarshal([]byte(tt.item), &item); err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		skipKids, err := tt.processor(&item)
+		if skipKids {
+			t.Errorf("%s: expected the kids not to be skipped", tt.name)
+		}
+		if keep := err == nil; keep != tt.keep {
+			t.Errorf("%s: expected keep to be %v, got error %v", tt.name, tt.keep, err)
+		}
+	}
+}
+
+func TestPointsPerHour(t *testing.T) {
+	score := 10
+	createdAt := 1700000000
+	created := time.Unix(int64(createdAt), 0)
+	item := &gohn.Item{Score: &score, Time: &createdAt}
+
+	tests := []struct {
+		now  time.Time
+		want float64
+	}{
+		{created.Add(4 * time.Hour), 2.5},
+		{created.Add(30 * time.Minute), 20},
+		{created.Add(10 * time.Second), 600},
+		{created.Add(-time.Hour), 600},
+	}
+	for _, tt := range tests {
+		if got := processors.PointsPerHour(item, tt.now); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("PointsPerHour at %v = %v, want %v", tt.now.Sub(created), got, tt.want)
+		}
+	}
+	if got := processors.PointsPerHour(&gohn.Item{Score: &score}, created); got != 0 {
+		t.Errorf("expected 0 for an item without time, got %v", got)
+	}
+}
@@ -3,7 +3,6 @@
 import (
 	"net/http"
 	"net/http/httptest"
-	"net/url"
 
 	"github.com/alexferrari88/gohn/pkg/gohn"
 )
@@ -12,7 +11,9 @@ const (
 	baseTestURLPath = "/hn-v0"
 )
 
-func Init() (client *gohn.Client, mux *http.ServeMux, serverURL string, teardown func()) {
+// Init starts a test server and returns a client pointed at it.
+// opts are passed to gohn.NewClient.
+func Init(opts ...gohn.Option) (client *gohn.Client, mux *http.ServeMux, serverURL string, teardown func()) {
 	mux = http.NewServeMux()
 
 	apiHandler := http.NewServeMux()
@@ -20,13 +21,11 @@ func Init() (client *gohn.Client, mux *http.ServeMux, serverURL string, teardown
 
 	server := httptest.NewServer(apiHandler)
 
-	client, err := gohn.NewClient(nil)
+	opts = append([]gohn.Option{gohn.WithBaseURL(server.URL + baseTestURLPath + "/")}, opts...)
+	client, err := gohn.NewClient(opts...)
 	if err != nil {
 		panic(err)
 	}
 
-	url, _ := url.Parse(server.URL + baseTestURLPath + "/")
-	client.BaseURL = url
-
 	return client, mux, server.URL, server.Close
 }